@@ -0,0 +1,198 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// The scope used for impersonated credentials, unless overridden via the
+// scopes option.
+const defaultImpersonationScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// How often access token files are re-read, unless overridden via the
+// accessTokenRefresh option.
+const defaultAccessTokenRefresh = time.Minute
+
+// Returns the client options that control how the BigQuery client
+// authenticates.
+func (c Config) authOptions() ([]option.ClientOption, error) {
+	if err := checkAuthConflicts(c); err != nil {
+		return nil, err
+	}
+
+	var options []option.ClientOption
+	if c.APIKey != "" {
+		options = append(options, option.WithAPIKey(c.APIKey))
+	}
+	if c.Credentials != nil {
+		options = append(options, option.WithCredentialsJSON(c.Credentials))
+	}
+	if c.CredentialsFile != "" {
+		options = append(options, option.WithCredentialsFile(c.CredentialsFile))
+	}
+	if c.ExternalAccountFile != "" {
+		if err := checkExternalAccountFile(c.ExternalAccountFile); err != nil {
+			return nil, err
+		}
+		options = append(options, option.WithCredentialsFile(c.ExternalAccountFile))
+	}
+	if c.AccessTokenFile != "" {
+		refresh := c.AccessTokenRefresh
+		if refresh == 0 {
+			refresh = defaultAccessTokenRefresh
+		}
+		options = append(options, option.WithTokenSource(newFileTokenSource(c.AccessTokenFile, refresh)))
+	}
+	if c.DisableAuth {
+		options = append(options, option.WithoutAuthentication())
+	}
+
+	// Impersonation uses the credentials configured above (or the application
+	// default credentials) as the base credentials.
+	if c.ImpersonateServiceAccount != "" {
+		scopes := c.Scopes
+		if scopes == nil {
+			scopes = []string{defaultImpersonationScope}
+		}
+		tokenSource, err := impersonate.CredentialsTokenSource(
+			context.Background(),
+			impersonate.CredentialsConfig{
+				TargetPrincipal: c.ImpersonateServiceAccount,
+				Scopes:          scopes,
+				Delegates:       c.Delegates,
+			},
+			options...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		options = []option.ClientOption{option.WithTokenSource(tokenSource)}
+	}
+
+	if c.QuotaProject != "" {
+		options = append(options, option.WithQuotaProject(c.QuotaProject))
+	}
+	if c.UniverseDomain != "" {
+		options = append(options, option.WithUniverseDomain(c.UniverseDomain))
+	}
+	return options, nil
+}
+
+func checkAuthConflicts(c Config) error {
+	var sources []string
+	for _, source := range []struct {
+		name string
+		set  bool
+	}{
+		{"apiKey", c.APIKey != ""},
+		{"credentials", c.Credentials != nil},
+		{"credentialsFile", c.CredentialsFile != ""},
+		{"externalAccountFile", c.ExternalAccountFile != ""},
+		{"accessTokenFile", c.AccessTokenFile != ""},
+		{"disableAuth", c.DisableAuth},
+	} {
+		if source.set {
+			sources = append(sources, source.name)
+		}
+	}
+	if len(sources) > 1 {
+		return fmt.Errorf("conflicting credential options: %s", strings.Join(sources, ", "))
+	}
+
+	impersonating := c.ImpersonateServiceAccount != ""
+	if impersonating && len(sources) == 1 && (sources[0] == "apiKey" || sources[0] == "disableAuth") {
+		return fmt.Errorf("impersonateServiceAccount cannot be combined with %s", sources[0])
+	}
+	if !impersonating && len(c.Delegates) > 0 {
+		return errors.New("delegates requires impersonateServiceAccount")
+	}
+	if c.AccessTokenFile == "" && c.AccessTokenRefresh != 0 {
+		return errors.New("accessTokenRefresh requires accessTokenFile")
+	}
+	return nil
+}
+
+// Checks that the file contains external account (workload identity
+// federation) credentials, to catch configuration mistakes early.
+func checkExternalAccountFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var file struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("invalid external account credentials file: %w", err)
+	}
+	if file.Type != "external_account" {
+		return fmt.Errorf("invalid external account credentials file: unexpected type %q", file.Type)
+	}
+	return nil
+}
+
+// Splits comma-separated values, allowing lists to be passed either as a
+// repeated option or as a single comma-separated option.
+func splitList(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
+
+// A token source that reads an access token from a file, and periodically
+// re-reads it (since the file is typically updated by an external process).
+type fileTokenSource struct {
+	filename string
+	refresh  time.Duration
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newFileTokenSource(filename string, refresh time.Duration) oauth2.TokenSource {
+	return &fileTokenSource{
+		filename: filename,
+		refresh:  refresh,
+	}
+}
+
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Now().Before(s.token.Expiry) {
+		return s.token, nil
+	}
+
+	data, err := os.ReadFile(s.filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading access token file: %w", err)
+	}
+	accessToken := strings.TrimSpace(string(data))
+	if accessToken == "" {
+		return nil, fmt.Errorf("access token file is empty: %s", s.filename)
+	}
+
+	s.token = &oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(s.refresh),
+	}
+	return s.token, nil
+}
@@ -3,9 +3,14 @@ package bigquery
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"cloud.google.com/go/bigquery"
 	"google.golang.org/api/option"
 )
 
@@ -13,11 +18,244 @@ type Config struct {
 	ProjectID string
 	Dataset   string
 	Location  string
-	Options   []option.ClientOption
+
+	// DatasetProject is the project containing the Dataset, if it differs from
+	// ProjectID (the project in which queries are run and billed).
+	DatasetProject string
+
+	// Options are additional client options, which are applied after the
+	// options derived from the fields below. They can't be represented in a
+	// DSN, and are therefore omitted by [Config.FormatDSN].
+	Options []option.ClientOption
+
+	// APIKey is the API key used for authentication.
+	APIKey string
+
+	// Credentials is a service account or refresh token credentials JSON
+	// object.
+	Credentials []byte
+
+	// CredentialsFile is the path to a file containing a service account or
+	// refresh token credentials JSON object.
+	CredentialsFile string
+
+	// ExternalAccountFile is the path to a file containing external account
+	// (workload identity federation) credentials.
+	ExternalAccountFile string
+
+	// AccessTokenFile is the path to a file containing an OAuth2 access
+	// token, which is re-read every AccessTokenRefresh (one minute by
+	// default).
+	AccessTokenFile    string
+	AccessTokenRefresh time.Duration
+
+	// ImpersonateServiceAccount is the email of a service account to
+	// impersonate (via the given Delegates, if any), using the other
+	// credentials as the base credentials.
+	ImpersonateServiceAccount string
+	Delegates                 []string
+
+	// QuotaProject is the project used for quota and billing purposes.
+	QuotaProject string
+
+	// UniverseDomain overrides the default universe domain.
+	UniverseDomain string
+
+	// Scopes overrides the default OAuth2 scopes.
+	Scopes []string
+
+	// Endpoint overrides the default endpoint.
+	Endpoint string
+
+	// UserAgent sets the User-Agent used for requests to the BigQuery API.
+	UserAgent string
+
+	// DisableAuth disables all authentication methods.
+	DisableAuth bool
+
+	// SessionSetup are statements run whenever a connection creates a new
+	// session (including when a session is recreated after it expired), e.g.
+	// to declare variables or create temporary tables.
+	SessionSetup []string
+
+	// DryRun causes all queries to be dry run, in which case Query returns
+	// rows with the schema the results would have (but no rows), and Exec
+	// doesn't affect any rows. Transaction control and SessionSetup
+	// statements are still run. See also [DryRun].
+	DryRun bool
+
+	// OnDemandPricePerTiB is the on-demand price per TiB processed used by
+	// [DryRun] to estimate the cost of queries (6.25 USD by default).
+	OnDemandPricePerTiB float64
+
+	// QuerySettings are the default settings for queries, which can be
+	// overridden per query via [WithQuerySettings].
+	QuerySettings
+
+	// MaxConcurrentJobs limits the number of jobs run concurrently by the
+	// connections of a connector (0 means unlimited). Jobs that would exceed
+	// the limit wait in a FIFO queue (until their context is done). Batch
+	// jobs are subject to the same limit, unless MaxConcurrentBatchJobs is
+	// set. See also [QueueStats].
+	MaxConcurrentJobs      int
+	MaxConcurrentBatchJobs int
+
+	// BatchJobWeight is the number of jobs each batch job counts as towards
+	// MaxConcurrentJobs (1 by default), if batch jobs don't have their own
+	// limit.
+	BatchJobWeight int
+
+	// PingMode selects how thoroughly connections are checked by Ping
+	// (PingModeLight by default). See [PingMode].
+	PingMode PingMode
+
+	// Labels are the default job labels applied to every query. They can be
+	// extended or overridden per query via [WithLabels].
+	Labels map[string]string
+
+	// Interceptors wrap the execution of every query, in the order given (the
+	// first interceptor is the outermost one). See [Interceptor].
+	Interceptors []Interceptor
+
+	// GeographyFormat specifies the representation used for GEOGRAPHY values
+	// (WKT strings by default).
+	GeographyFormat GeographyFormat
+
+	// JSONRawMessage causes JSON values to be returned as [json.RawMessage]
+	// values, which means that JSON values nested in ARRAY/STRUCT values are
+	// embedded verbatim (rather than as base64-encoded strings).
+	JSONRawMessage bool
+
+	// TypeMapping selects the policy used to convert BigQuery values to Go
+	// values (TypeMappingStrict by default). See [TypeMapping].
+	TypeMapping TypeMapping
+
+	// TimeZone is the location of the time.Time values returned for DATE and
+	// DATETIME values by the native type mapping (UTC by default).
+	TimeZone *time.Location
+
+	// Converters override the conversion of values of particular BigQuery
+	// types, regardless of the TypeMapping.
+	Converters map[bigquery.FieldType]Converter
+}
+
+// The value substituted for secrets by [Config.String].
+const redacted = "REDACTED"
+
+// FormatDSN returns the canonical DSN representing the config, which can be
+// passed to [sql.Open]. Fields that can't be represented in a DSN (Options,
+// SessionSetup, Labels, Interceptors and Converters) are omitted.
+func (c Config) FormatDSN() string {
+	return c.formatDSN(false)
+}
+
+// String returns the DSN representing the config (see [Config.FormatDSN]),
+// with secrets redacted, which makes it suitable for logging.
+func (c Config) String() string {
+	return c.formatDSN(true)
+}
+
+func (c Config) formatDSN(redact bool) string {
+	query := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	secret := func(value string) string {
+		if redact && value != "" {
+			return redacted
+		}
+		return value
+	}
+
+	set("apiKey", secret(c.APIKey))
+	set("credentials", secret(base64.RawURLEncoding.EncodeToString(c.Credentials)))
+	set("credentialsFile", c.CredentialsFile)
+	set("externalAccountFile", c.ExternalAccountFile)
+	set("accessTokenFile", c.AccessTokenFile)
+	if c.AccessTokenRefresh != 0 {
+		set("accessTokenRefresh", c.AccessTokenRefresh.String())
+	}
+	set("impersonateServiceAccount", c.ImpersonateServiceAccount)
+	set("delegates", strings.Join(c.Delegates, ","))
+	set("quotaProject", c.QuotaProject)
+	set("universeDomain", c.UniverseDomain)
+	for _, scope := range c.Scopes {
+		query.Add("scopes", scope)
+	}
+	set("endpoint", c.Endpoint)
+	set("userAgent", c.UserAgent)
+	if c.DisableAuth {
+		set("disableAuth", "true")
+	}
+
+	if c.DryRun {
+		set("dryRun", "true")
+	}
+	if c.OnDemandPricePerTiB != 0 {
+		set("onDemandPricePerTiB", strconv.FormatFloat(c.OnDemandPricePerTiB, 'g', -1, 64))
+	}
+	set("priority", strings.ToLower(string(c.Priority)))
+	set("reservation", c.Reservation)
+	set("kmsKeyName", c.KMSKeyName)
+	if c.MaxSlots != 0 {
+		set("maxSlots", strconv.Itoa(c.MaxSlots))
+	}
+	if c.MaxConcurrentJobs != 0 {
+		set("maxConcurrentJobs", strconv.Itoa(c.MaxConcurrentJobs))
+	}
+	if c.MaxConcurrentBatchJobs != 0 {
+		set("maxConcurrentBatchJobs", strconv.Itoa(c.MaxConcurrentBatchJobs))
+	}
+	if c.BatchJobWeight != 0 {
+		set("batchJobWeight", strconv.Itoa(c.BatchJobWeight))
+	}
+	set("pingMode", string(c.PingMode))
+	set("geographyFormat", string(c.GeographyFormat))
+	if c.JSONRawMessage {
+		set("jsonRawMessage", "true")
+	}
+	set("typeMapping", string(c.TypeMapping))
+	if c.TimeZone != nil {
+		set("timeZone", c.TimeZone.String())
+	}
+
+	// A location without a dataset can't be represented in the path.
+	dataset := c.Dataset
+	if dataset != "" && c.DatasetProject != "" {
+		dataset = c.DatasetProject + "." + dataset
+	} else {
+		set("datasetProject", c.DatasetProject)
+	}
+	path := ""
+	switch {
+	case c.Location != "" && dataset != "":
+		path = "/" + c.Location + "/" + dataset
+	case dataset != "":
+		path = "/" + dataset
+	case c.Location != "":
+		set("location", c.Location)
+	}
+
+	u := url.URL{
+		Scheme:   "bigquery",
+		Host:     c.ProjectID,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// ParseDSN parses a DSN (as accepted by [sql.Open]) into a config, e.g. to
+// adjust it before passing it to [NewConnector].
+func ParseDSN(dsn string) (Config, error) {
+	return parseDSN(dsn)
 }
 
 // Parses DSN of the form:
-// bigquery://projectID[/location][/dataset]?key=val
+// bigquery://projectID[/location][/[datasetProject.]dataset]?key=val
 func parseDSN(dsn string) (Config, error) {
 	url, err := url.Parse(dsn)
 	if err != nil {
@@ -30,66 +268,218 @@ func parseDSN(dsn string) (Config, error) {
 		}
 	}
 
+	projectID, err := parseProjectID(url)
+	if err != nil {
+		return Config{}, &invalidConnStrError{Err: err}
+	}
 	location, dataset, err := parseLocationDataset(url)
 	if err != nil {
 		return Config{}, &invalidConnStrError{Err: err}
 	}
-
-	options, err := parseOptions(url)
+	datasetProject, dataset, err := parseDatasetProject(url, dataset)
 	if err != nil {
 		return Config{}, &invalidConnStrError{Err: err}
 	}
 
-	return Config{
-		ProjectID: url.Hostname(),
-		Location:  location,
-		Dataset:   dataset,
-		Options:   options,
-	}, nil
+	config := Config{
+		ProjectID:      projectID,
+		Location:       location,
+		Dataset:        dataset,
+		DatasetProject: datasetProject,
+	}
+	if err := parseOptions(url, &config); err != nil {
+		return Config{}, &invalidConnStrError{Err: err}
+	}
+	if err := parseDriverOptions(url, &config); err != nil {
+		return Config{}, &invalidConnStrError{Err: err}
+	}
+	return config, nil
+}
+
+// Project IDs consist of letters, digits, hyphens and underscores, and (for
+// domain-scoped projects) dots.
+var projectIDRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Returns the project ID, which is the host of the URL (without a port or
+// user info, which would otherwise be dropped silently).
+func parseProjectID(url *url.URL) (string, error) {
+	if url.User != nil {
+		return "", fmt.Errorf("unexpected user info: %s", url.User.Username())
+	}
+	if url.Host == "" {
+		return "", fmt.Errorf("missing project ID")
+	}
+	if !projectIDRegexp.MatchString(url.Host) {
+		return "", fmt.Errorf("invalid project ID: %s", url.Host)
+	}
+	return url.Host, nil
 }
 
 func parseLocationDataset(url *url.URL) (string, string, error) {
 	fields := strings.Split(strings.Trim(url.Path, "/"), "/")
+	location := url.Query().Get("location")
+	if strings.Contains(location, "/") {
+		return "", "", fmt.Errorf("invalid location: %s", location)
+	}
 	switch len(fields) {
 	case 0:
-		return "", "", nil
+		return location, "", nil
 	case 1:
-		return "", fields[0], nil
+		return location, fields[0], nil
 	case 2:
+		if location != "" {
+			return "", "", fmt.Errorf("location specified in both path and options: %s", url.Path)
+		}
 		return fields[0], fields[1], nil
 	default:
 		return "", "", fmt.Errorf("too many path segments: %s", url.Path)
 	}
 }
 
-func parseOptions(url *url.URL) ([]option.ClientOption, error) {
+// Splits a dataset of the form "project.dataset" (dataset IDs can't contain
+// dots, but domain-scoped project IDs can), and merges the project with the
+// datasetProject option.
+func parseDatasetProject(url *url.URL, dataset string) (string, string, error) {
+	project := url.Query().Get("datasetProject")
+	if strings.Contains(project, "/") {
+		return "", "", fmt.Errorf("invalid datasetProject: %s", project)
+	}
+	i := strings.LastIndex(dataset, ".")
+	if i < 0 {
+		return project, dataset, nil
+	}
+	if i == 0 || i == len(dataset)-1 {
+		return "", "", fmt.Errorf("invalid dataset: %s", dataset)
+	}
+	if project != "" && project != dataset[:i] {
+		return "", "", fmt.Errorf("conflicting dataset projects: %s and %s", dataset[:i], project)
+	}
+	return dataset[:i], dataset[i+1:], nil
+}
+
+// Parses the options that configure the underlying BigQuery client.
+func parseOptions(url *url.URL, config *Config) error {
 	query := url.Query()
 
-	var options []option.ClientOption
-	if apiKey := query.Get("apiKey"); apiKey != "" {
-		options = append(options, option.WithAPIKey(apiKey))
-	}
+	config.APIKey = query.Get("apiKey")
 	if credentials := query.Get("credentials"); credentials != "" {
 		decoded, err := base64.RawURLEncoding.DecodeString(credentials)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		options = append(options, option.WithCredentialsJSON([]byte(decoded)))
+		config.Credentials = decoded
 	}
-	if credentialsFile := query.Get("credentialsFile"); credentialsFile != "" {
-		options = append(options, option.WithCredentialsFile(credentialsFile))
+	config.CredentialsFile = query.Get("credentialsFile")
+	config.ExternalAccountFile = query.Get("externalAccountFile")
+	config.AccessTokenFile = query.Get("accessTokenFile")
+	if refresh := query.Get("accessTokenRefresh"); refresh != "" {
+		d, err := time.ParseDuration(refresh)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid accessTokenRefresh: %s", refresh)
+		}
+		config.AccessTokenRefresh = d
 	}
-	if scopes := query["scopes"]; scopes != nil {
-		options = append(options, option.WithScopes(scopes...))
+	config.ImpersonateServiceAccount = query.Get("impersonateServiceAccount")
+	config.Delegates = splitList(query["delegates"])
+	config.QuotaProject = query.Get("quotaProject")
+	config.UniverseDomain = query.Get("universeDomain")
+	config.Scopes = query["scopes"]
+	config.Endpoint = query.Get("endpoint")
+	config.UserAgent = query.Get("userAgent")
+	config.DisableAuth = query.Get("disableAuth") == "true"
+
+	return checkAuthConflicts(*config)
+}
+
+// Parses the options that configure the driver itself, rather than the
+// underlying BigQuery client.
+func parseDriverOptions(url *url.URL, config *Config) error {
+	query := url.Query()
+
+	if dryRun := query.Get("dryRun"); dryRun == "true" {
+		config.DryRun = true
+	}
+	if price := query.Get("onDemandPricePerTiB"); price != "" {
+		p, err := strconv.ParseFloat(price, 64)
+		if err != nil || p < 0 || math.IsInf(p, 0) || math.IsNaN(p) {
+			return fmt.Errorf("invalid onDemandPricePerTiB: %s", price)
+		}
+		config.OnDemandPricePerTiB = p
+	}
+	if priority := query.Get("priority"); priority != "" {
+		p, err := parsePriority(priority)
+		if err != nil {
+			return err
+		}
+		config.Priority = p
+	}
+	config.Reservation = query.Get("reservation")
+	config.KMSKeyName = query.Get("kmsKeyName")
+	for _, limit := range []struct {
+		key   string
+		value *int
+	}{
+		{"maxConcurrentJobs", &config.MaxConcurrentJobs},
+		{"maxConcurrentBatchJobs", &config.MaxConcurrentBatchJobs},
+		{"batchJobWeight", &config.BatchJobWeight},
+		{"maxSlots", &config.MaxSlots},
+	} {
+		if value := query.Get(limit.key); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid %s: %s", limit.key, value)
+			}
+			*limit.value = n
+		}
+	}
+	if mode := query.Get("pingMode"); mode != "" {
+		pingMode, err := parsePingMode(mode)
+		if err != nil {
+			return err
+		}
+		config.PingMode = pingMode
+	}
+	if format := query.Get("geographyFormat"); format != "" {
+		geographyFormat, err := parseGeographyFormat(format)
+		if err != nil {
+			return err
+		}
+		config.GeographyFormat = geographyFormat
+	}
+	if jsonRawMessage := query.Get("jsonRawMessage"); jsonRawMessage == "true" {
+		config.JSONRawMessage = true
+	}
+	if mapping := query.Get("typeMapping"); mapping != "" {
+		typeMapping, err := parseTypeMapping(mapping)
+		if err != nil {
+			return err
+		}
+		config.TypeMapping = typeMapping
+	}
+	if timeZone := query.Get("timeZone"); timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return err
+		}
+		config.TimeZone = loc
+	}
+	return config.QuerySettings.validate()
+}
+
+// Returns the client options corresponding to the config.
+func (c Config) clientOptions() ([]option.ClientOption, error) {
+	options, err := c.authOptions()
+	if err != nil {
+		return nil, err
 	}
-	if endpoint := query.Get("endpoint"); endpoint != "" {
-		options = append(options, option.WithEndpoint(endpoint))
+	if c.Scopes != nil {
+		options = append(options, option.WithScopes(c.Scopes...))
 	}
-	if userAgent := query.Get("userAgent"); userAgent != "" {
-		options = append(options, option.WithUserAgent(userAgent))
+	if c.Endpoint != "" {
+		options = append(options, option.WithEndpoint(c.Endpoint))
 	}
-	if disableAuth := query.Get("disableAuth"); disableAuth == "true" {
-		options = append(options, option.WithoutAuthentication())
+	if c.UserAgent != "" {
+		options = append(options, option.WithUserAgent(c.UserAgent))
 	}
-	return options, nil
+	return append(options, c.Options...), nil
 }
@@ -0,0 +1,77 @@
+package bigquery
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func FuzzParseFormatDSN(f *testing.F) {
+	// The DSNs from the README, and some using the documented options.
+	for _, dsn := range []string{
+		"bigquery://projectID",
+		"bigquery://billing-project/US/data-project.dataset",
+		"bigquery://PROJECT_ID/LOCATION/DATASET",
+		"bigquery://PROJECT_ID/LOCATION/DATASET?credentialsFile=/path/to/credentials.json",
+		"bigquery://PROJECT_ID/DATASET?apiKey=secret&location=EU",
+		"bigquery://PROJECT_ID/US/DATASET?credentials=eyJ0eXBlIjoic2VydmljZV9hY2NvdW50In0",
+		"bigquery://PROJECT_ID?datasetProject=data-project&impersonateServiceAccount=sa@example.iam.gserviceaccount.com&delegates=a,b",
+		"bigquery://PROJECT_ID/DATASET?accessTokenFile=/tmp/token&accessTokenRefresh=30s&quotaProject=quota",
+		"bigquery://PROJECT_ID/DATASET?disableAuth=true&endpoint=http://localhost:9050&scopes=a&scopes=b&userAgent=test",
+		"bigquery://PROJECT_ID/DATASET?dryRun=true&onDemandPricePerTiB=5.5&priority=batch&kmsKeyName=key",
+		"bigquery://PROJECT_ID/DATASET?maxConcurrentJobs=10&maxConcurrentBatchJobs=2&batchJobWeight=3&pingMode=full",
+		"bigquery://PROJECT_ID/DATASET?geographyFormat=geojson&jsonRawMessage=true&typeMapping=native&timeZone=Europe/Paris",
+	} {
+		f.Add(dsn)
+	}
+
+	f.Fuzz(func(t *testing.T, dsn string) {
+		config, err := ParseDSN(dsn)
+		if err != nil {
+			t.Skip()
+		}
+
+		formatted := config.FormatDSN()
+		parsed, err := ParseDSN(formatted)
+		if err != nil {
+			t.Fatalf("ParseDSN(%q) (formatted from %q): %v", formatted, dsn, err)
+		}
+		if !reflect.DeepEqual(parsed, config) {
+			t.Fatalf("ParseDSN(%q) = %+v, want %+v (parsed from %q)", formatted, parsed, config, dsn)
+		}
+
+		s := config.String()
+		redacted, err := ParseDSN(s)
+		if err != nil {
+			t.Fatalf("ParseDSN(%q) (redacted from %q): %v", s, dsn, err)
+		}
+		if config.APIKey != "" && redacted.APIKey != "REDACTED" {
+			t.Errorf("String() = %q, which doesn't redact the API key", s)
+		}
+		if len(config.Credentials) > 0 && base64.RawURLEncoding.EncodeToString(redacted.Credentials) != "REDACTED" {
+			t.Errorf("String() = %q, which doesn't redact the credentials", s)
+		}
+		redacted.APIKey, redacted.Credentials = config.APIKey, config.Credentials
+		if !reflect.DeepEqual(redacted, config) {
+			t.Errorf("ParseDSN(%q) = %+v, want %+v with credentials redacted", s, redacted, config)
+		}
+	})
+}
+
+func TestParseDSNInvalidHost(t *testing.T) {
+	for _, dsn := range []string{
+		"bigquery://",
+		"bigquery:///dataset",
+		"bigquery://::",
+		"bigquery://project:9050/dataset",
+		"bigquery://project:/dataset",
+		"bigquery://[::1]/dataset",
+		"bigquery://user@project/dataset",
+		"bigquery://-project/dataset",
+		"bigquery://my%20project/dataset",
+	} {
+		if config, err := ParseDSN(dsn); err == nil {
+			t.Errorf("ParseDSN(%q) = %+v, want an error", dsn, config)
+		}
+	}
+}
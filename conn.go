@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 )
@@ -25,27 +26,43 @@ var (
 type conn struct {
 	client    *bigquery.Client
 	config    Config
+	limiter   *jobLimiter
 	sessionID string
+	tx        *tx
+	txState   txState
 	closed    bool
 	invalid   bool
-	options
+
+	// When the session was created and last used.
+	sessionCreated time.Time
+	sessionUsed    time.Time
+
+	// The variables declared in the session.
+	variables []string
 }
 
-func (c *conn) Ping(ctx context.Context) error {
-	if _, err := c.client.Dataset(c.config.Dataset).Metadata(ctx); err != nil {
-		return err
+// Returns the default dataset, which may be in a different project than the
+// one queries are run in.
+func (c *conn) dataset() *bigquery.Dataset {
+	if c.config.DatasetProject != "" {
+		return c.client.DatasetInProject(c.config.DatasetProject, c.config.Dataset)
 	}
-	return nil
+	return c.client.Dataset(c.config.Dataset)
 }
 
 func (c *conn) IsValid() bool {
-	return !c.invalid && !c.closed
+	return !c.invalid && !c.closed && !c.sessionExpiring()
 }
 
 func (c *conn) ResetSession(ctx context.Context) error {
 	if !c.IsValid() {
 		return driver.ErrBadConn
 	}
+
+	// Never return a connection with an open transaction to the pool.
+	if err := c.rollbackTx(ctx); err != nil || !c.IsValid() {
+		return driver.ErrBadConn
+	}
 	return nil
 }
 
@@ -89,11 +106,12 @@ func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, e
 		return nil, errors.New("read-only transactions not supported")
 	}
 
-	if _, err := c.ExecContext(ctx, "BEGIN TRANSACTION;", nil); err != nil {
+	if _, err := c.execTxStatement(ctx, "BEGIN TRANSACTION;"); err != nil {
 		return nil, err
 	}
 
-	return &tx{conn: c}, nil
+	c.tx = &tx{conn: c}
+	return c.tx, nil
 }
 
 func (c *conn) Close() error {
@@ -119,12 +137,15 @@ func (c *conn) abortSession(ctx context.Context) error {
 		return nil
 	}
 
-	if _, err := c.ExecContext(
-		context.Background(), "CALL BQ.ABORT_SESSION();", nil,
-	); err != nil {
+	// Aborting the session also terminates any open transaction.
+	c.tx = nil
+	c.txState = txNone
+
+	abort := &stmt{conn: c, query: "CALL BQ.ABORT_SESSION();", internal: true}
+	if _, err := abort.ExecContext(context.Background(), nil); err != nil {
 		return err
 	}
 
-	c.sessionID = ""
+	c.forgetSession()
 	return nil
 }
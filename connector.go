@@ -12,12 +12,14 @@ var (
 )
 
 type connector struct {
-	config Config
+	config  Config
+	limiter *jobLimiter
 }
 
 func NewConnector(config Config) driver.Connector {
 	return &connector{
-		config: config,
+		config:  config,
+		limiter: newJobLimiter(config),
 	}
 }
 
@@ -26,10 +28,14 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	// working when the context is cancelled (whereas the context provided to
 	// this function should only control the lifetime of the connection event
 	// itself).
+	options, err := c.config.clientOptions()
+	if err != nil {
+		return nil, err
+	}
 	client, err := bigquery.NewClient(
 		context.Background(),
 		c.config.ProjectID,
-		c.config.Options...,
+		options...,
 	)
 	if err != nil {
 		return nil, err
@@ -37,11 +43,12 @@ func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
 	client.Location = c.config.Location
 
 	return &conn{
-		client: client,
-		config: c.config,
+		client:  client,
+		config:  c.config,
+		limiter: c.limiter,
 	}, nil
 }
 
 func (c *connector) Driver() driver.Driver {
-	return &bigQueryDriver{}
+	return &bigQueryDriver{connector: c}
 }
@@ -0,0 +1,141 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+)
+
+type labelsKey struct{}
+type jobIDPrefixKey struct{}
+type getQueryKey struct{}
+type getJobKey struct{}
+type destinationKey struct{}
+type externalTablesKey struct{}
+
+// WithGetQuery returns a copy of the context with the given [GetQuery]
+// function attached. It behaves the same as passing the function as a
+// Query/Exec argument, except that it applies to every query executed with the
+// returned context. This is useful when the arguments are handled by an ORM or
+// query builder that doesn't allow function-valued arguments.
+func WithGetQuery(ctx context.Context, getQuery GetQuery) context.Context {
+	return context.WithValue(ctx, getQueryKey{}, getQuery)
+}
+
+func getQueryFromContext(ctx context.Context) GetQuery {
+	getQuery, _ := ctx.Value(getQueryKey{}).(GetQuery)
+	return getQuery
+}
+
+// WithGetJob returns a copy of the context with the given [GetJob] function
+// attached. It behaves the same as passing the function as a Query/Exec
+// argument, except that it applies to every query executed with the returned
+// context.
+func WithGetJob(ctx context.Context, getJob GetJob) context.Context {
+	return context.WithValue(ctx, getJobKey{}, getJob)
+}
+
+func getJobFromContext(ctx context.Context) GetJob {
+	getJob, _ := ctx.Value(getJobKey{}).(GetJob)
+	return getJob
+}
+
+// WithDestination returns a copy of the context with the given [Destination]
+// attached. It behaves the same as passing the destination as a Query/Exec
+// argument, except that it applies to every query executed with the returned
+// context.
+func WithDestination(ctx context.Context, destination Destination) context.Context {
+	return context.WithValue(ctx, destinationKey{}, &destination)
+}
+
+func destinationFromContext(ctx context.Context) *Destination {
+	destination, _ := ctx.Value(destinationKey{}).(*Destination)
+	return destination
+}
+
+// WithExternalTables returns a copy of the context with the given
+// [ExternalTables] attached, in addition to (and taking precedence over) any
+// external tables attached to the parent context. They're available to every
+// query executed with the returned context.
+func WithExternalTables(ctx context.Context, tables ExternalTables) context.Context {
+	return context.WithValue(ctx, externalTablesKey{}, mergeExternalTables(externalTablesFromContext(ctx), tables))
+}
+
+func externalTablesFromContext(ctx context.Context) ExternalTables {
+	tables, _ := ctx.Value(externalTablesKey{}).(ExternalTables)
+	return tables
+}
+
+// WithLabels returns a copy of the context with the given job labels attached.
+// The labels are applied to every query executed with the returned context,
+// in addition to (and taking precedence over) the default labels configured
+// via [Config.Labels] and any labels attached to the parent context.
+func WithLabels(ctx context.Context, labels map[string]string) context.Context {
+	merged := maps.Clone(labelsFromContext(ctx))
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	maps.Copy(merged, labels)
+	return context.WithValue(ctx, labelsKey{}, merged)
+}
+
+func labelsFromContext(ctx context.Context) map[string]string {
+	labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+	return labels
+}
+
+// WithJobIDPrefix returns a copy of the context with the given job ID prefix
+// attached. Jobs for queries executed with the returned context will have IDs
+// consisting of the prefix followed by a random suffix.
+func WithJobIDPrefix(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, jobIDPrefixKey{}, prefix)
+}
+
+func jobIDPrefixFromContext(ctx context.Context) string {
+	prefix, _ := ctx.Value(jobIDPrefixKey{}).(string)
+	return prefix
+}
+
+// Label requirements, as specified in the BigQuery docs:
+// https://cloud.google.com/bigquery/docs/labels-intro#requirements
+const maxLabels = 64
+
+// The maximum length of a label value.
+const maxLabelLength = 63
+
+var (
+	labelKeyRegexp   = regexp.MustCompile(`^[\p{Ll}\p{Lo}][\p{Ll}\p{Lo}\p{N}_-]{0,62}$`)
+	labelValueRegexp = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_-]{0,63}$`)
+	jobIDRegexp      = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`)
+)
+
+// Job IDs can be at most 1024 characters long, and the random suffix appended
+// by the BigQuery client takes up some of that.
+const maxJobIDPrefixLength = 1000
+
+// The maximum length of a job ID, as specified in the BigQuery docs:
+// https://cloud.google.com/bigquery/docs/running-jobs#generate-jobid
+const maxJobIDLength = 1024
+
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("too many labels: %d (maximum is %d)", len(labels), maxLabels)
+	}
+	for key, value := range labels {
+		if !labelKeyRegexp.MatchString(key) {
+			return fmt.Errorf("invalid label key: %q", key)
+		}
+		if !labelValueRegexp.MatchString(value) {
+			return fmt.Errorf("invalid value for label %s: %q", key, value)
+		}
+	}
+	return nil
+}
+
+func validateJobIDPrefix(prefix string) error {
+	if len(prefix) > maxJobIDPrefixLength || !jobIDRegexp.MatchString(prefix) {
+		return fmt.Errorf("invalid job ID prefix: %q", prefix)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package bigquery
+
+import (
+	"errors"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Destination configures a table that the results of a query are written to.
+// It can be passed as an argument to a Query or Exec method (in which case it
+// applies to that query only), or attached to a context via
+// [WithDestination].
+type Destination struct {
+	// ProjectID and DatasetID default to the project and dataset of the
+	// connector's default dataset.
+	ProjectID string
+	DatasetID string
+	TableID   string
+
+	// WriteDisposition specifies how existing data in the table is treated
+	// (WRITE_EMPTY by default).
+	WriteDisposition bigquery.TableWriteDisposition
+
+	// CreateDisposition specifies whether the table is created if it doesn't
+	// exist (CREATE_IF_NEEDED by default).
+	CreateDisposition bigquery.TableCreateDisposition
+
+	// TimePartitioning, RangePartitioning and Clustering configure the table
+	// if it's created by the query.
+	TimePartitioning  *bigquery.TimePartitioning
+	RangePartitioning *bigquery.RangePartitioning
+	Clustering        *bigquery.Clustering
+
+	// AllowLargeResults allows large results to be written (only relevant for
+	// legacy SQL queries).
+	AllowLargeResults bool
+}
+
+func (d Destination) apply(query *bigquery.Query, c *conn) error {
+	if d.TableID == "" {
+		return errors.New("destination table ID is required")
+	}
+
+	projectID := d.ProjectID
+	if projectID == "" {
+		projectID = c.config.DatasetProject
+	}
+	if projectID == "" {
+		projectID = c.config.ProjectID
+	}
+	datasetID := d.DatasetID
+	if datasetID == "" {
+		datasetID = c.config.Dataset
+	}
+	if datasetID == "" {
+		return errors.New("destination dataset ID is required (there's no default dataset)")
+	}
+
+	query.Dst = c.client.DatasetInProject(projectID, datasetID).Table(d.TableID)
+	query.WriteDisposition = d.WriteDisposition
+	query.CreateDisposition = d.CreateDisposition
+	query.TimePartitioning = d.TimePartitioning
+	query.RangePartitioning = d.RangePartitioning
+	query.Clustering = d.Clustering
+	query.AllowLargeResults = d.AllowLargeResults
+	return nil
+}
+
+// Returns the number of rows written by the output stage of a SELECT query
+// (which, unlike the total number of rows in the results, excludes rows that
+// already existed in the destination table when appending to it).
+func rowsWritten(job *bigquery.Job) (int64, bool) {
+	details := queryStatistics(job)
+	if details == nil || len(details.QueryPlan) == 0 {
+		return 0, false
+	}
+
+	consumed := map[int64]bool{}
+	for _, stage := range details.QueryPlan {
+		for _, input := range stage.InputStages {
+			consumed[input] = true
+		}
+	}
+	var output *bigquery.ExplainQueryStage
+	for _, stage := range details.QueryPlan {
+		if !consumed[stage.ID] && (output == nil || stage.ID > output.ID) {
+			output = stage
+		}
+	}
+	if output == nil {
+		return 0, false
+	}
+	return output.RecordsWritten, true
+}
@@ -0,0 +1,83 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestRowsAffected(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		if q.Config.DestinationTable != nil && q.Config.WriteDisposition == "WRITE_APPEND" {
+			// The results include the 5 rows that were already in the table.
+			return fakeResult{
+				Schema: []*bq.TableFieldSchema{{Name: "x", Type: "INTEGER"}},
+				Rows:   [][]any{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}, {"6"}, {"7"}},
+				Stats: &bq.JobStatistics2{QueryPlan: []*bq.ExplainQueryStage{
+					{Id: 1, RecordsWritten: 4},
+					{Id: 2, InputStages: []int64{1}, RecordsWritten: 2},
+				}},
+			}
+		}
+		if q.SQL == "UPDATE t SET x = 1 WHERE true;" {
+			return fakeResult{DMLAffected: 3}
+		}
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "x", Type: "INTEGER"}},
+			Rows:   [][]any{{"1"}, {"2"}},
+		}
+	})
+	db := server.open(server.config())
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+		want  int64
+	}{
+		{"DML", "UPDATE t SET x = 1 WHERE true;", nil, 3},
+		{"new table", "SELECT x FROM t;", []any{Destination{TableID: "dst"}}, 2},
+		{
+			"truncated table",
+			"SELECT x FROM t;",
+			[]any{Destination{TableID: "dst", WriteDisposition: bigquery.WriteTruncate}},
+			2,
+		},
+		{
+			"appended table",
+			"SELECT x FROM t;",
+			[]any{&Destination{TableID: "dst", WriteDisposition: bigquery.WriteAppend}},
+			2,
+		},
+	}
+	for _, test := range tests {
+		result, err := db.ExecContext(ctx, test.query, test.args...)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if n, err := result.RowsAffected(); err != nil || n != test.want {
+			t.Errorf("%s: RowsAffected() = %d, %v, want %d", test.name, n, err, test.want)
+		}
+	}
+
+	withDestination := WithDestination(ctx, Destination{TableID: "dst", WriteDisposition: bigquery.WriteAppend})
+	result, err := db.ExecContext(withDestination, "SELECT x FROM t;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n != 2 {
+		t.Errorf("destination via the context: RowsAffected() = %d, %v, want 2", n, err)
+	}
+
+	result, err = db.ExecContext(ctx, "SELECT x FROM t;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := result.RowsAffected(); err == nil {
+		t.Errorf("no destination: RowsAffected() = %d, want an error", n)
+	}
+}
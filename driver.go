@@ -15,7 +15,10 @@ var (
 	_ driver.DriverContext = (*bigQueryDriver)(nil)
 )
 
-type bigQueryDriver struct{}
+type bigQueryDriver struct {
+	// The connector the driver was returned by, if any (see QueueStats).
+	connector *connector
+}
 
 func (b *bigQueryDriver) Open(dsn string) (driver.Conn, error) {
 	connector, err := b.OpenConnector(dsn)
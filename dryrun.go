@@ -0,0 +1,105 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// The default on-demand price (in USD per TiB processed) used to estimate the
+// cost of queries. See https://cloud.google.com/bigquery/pricing.
+const defaultOnDemandPricePerTiB = 6.25
+
+// Estimate is the result of a dry run of a query (see [DryRun]).
+type Estimate struct {
+	// TotalBytesProcessed is the number of bytes the query would process.
+	TotalBytesProcessed int64
+
+	// EstimatedCost is the cost of the query (in USD, unless a price in
+	// another currency is configured via [Config.OnDemandPricePerTiB]) under
+	// on-demand pricing, ignoring the minimum amount of data billed per
+	// query and table.
+	EstimatedCost float64
+
+	// Schema is the schema of the query's results.
+	Schema bigquery.Schema
+
+	// ReferencedTables are the tables the query would read from.
+	ReferencedTables []*bigquery.Table
+
+	// StatementType is the type of the query's statement (e.g. "SELECT").
+	StatementType string
+
+	// UndeclaredParameters are the names of the parameters referenced by the
+	// query that weren't passed as arguments.
+	UndeclaredParameters []string
+}
+
+// DryRun validates the query (and its arguments) without running it, and
+// estimates how much data it would process. Errors in the query or its
+// parameters are reported as errors.
+func DryRun(ctx context.Context, db *sql.DB, query string, args ...any) (*Estimate, error) {
+	sqlConn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlConn.Close()
+
+	pricePerTiB := defaultOnDemandPricePerTiB
+	if err := sqlConn.Raw(func(driverConn any) error {
+		if c, ok := driverConn.(*conn); ok && c.config.OnDemandPricePerTiB != 0 {
+			pricePerTiB = c.config.OnDemandPricePerTiB
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var job *bigquery.Job
+	args = append(args,
+		GetQuery(func(q *bigquery.Query) { q.DryRun = true }),
+		GetJob(func(j *bigquery.Job) { job = j }),
+	)
+	rows, err := sqlConn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	details := queryStatistics(job)
+	if details == nil {
+		return nil, ErrNoStats
+	}
+	return &Estimate{
+		TotalBytesProcessed:  details.TotalBytesProcessed,
+		EstimatedCost:        float64(details.TotalBytesProcessed) / (1 << 40) * pricePerTiB,
+		Schema:               details.Schema,
+		ReferencedTables:     details.ReferencedTables,
+		StatementType:        details.StatementType,
+		UndeclaredParameters: details.UndeclaredQueryParameterNames,
+	}, nil
+}
+
+// Returns the query statistics of the job, if available.
+func queryStatistics(job *bigquery.Job) *bigquery.QueryStatistics {
+	if job == nil {
+		return nil
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return nil
+	}
+	details, _ := status.Statistics.Details.(*bigquery.QueryStatistics)
+	return details
+}
+
+// Returns the schema the results of the dry run job would have.
+func dryRunSchema(job *bigquery.Job) bigquery.Schema {
+	if details := queryStatistics(job); details != nil {
+		return details.Schema
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package bigquery
+
+import (
+	"errors"
+	"maps"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// ExternalTables maps table names to definitions of external data (e.g. CSV,
+// Parquet, Avro, JSON or ORC files in Cloud Storage), which queries can then
+// reference by name as temporary tables. They can be passed as an argument to
+// a Query or Exec method (in which case they apply to that query only), or
+// attached to a context via [WithExternalTables].
+//
+//	tables := bigquery.ExternalTables{
+//		"events": {
+//			SourceFormat: bq.Parquet,
+//			SourceURIs:   []string{"gs://bucket/events/*.parquet"},
+//			HivePartitioningOptions: &bq.HivePartitioningOptions{
+//				Mode:            bq.AutoHivePartitioningMode,
+//				SourceURIPrefix: "gs://bucket/events/",
+//			},
+//		},
+//	}
+//	rows, err := db.QueryContext(ctx, "SELECT COUNT(*) FROM events;", tables)
+type ExternalTables map[string]*bigquery.ExternalDataConfig
+
+func (t ExternalTables) apply(query *bigquery.Query) error {
+	if len(t) == 0 {
+		return nil
+	}
+	if query.TableDefinitions == nil {
+		query.TableDefinitions = make(map[string]bigquery.ExternalData, len(t))
+	}
+	for name, config := range t {
+		if name == "" {
+			return errors.New("external table name is required")
+		}
+		if config == nil {
+			return errors.New("external table definition is required: " + name)
+		}
+		query.TableDefinitions[name] = config
+	}
+	return nil
+}
+
+// Merges the tables, with later tables taking precedence.
+func mergeExternalTables(tables ...ExternalTables) ExternalTables {
+	var merged ExternalTables
+	for _, t := range tables {
+		if len(t) == 0 {
+			continue
+		}
+		if merged == nil {
+			merged = ExternalTables{}
+		}
+		maps.Copy(merged, t)
+	}
+	return merged
+}
@@ -0,0 +1,177 @@
+package bigquery
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+var (
+	_ sql.Scanner   = (*Geography)(nil)
+	_ driver.Valuer = Geography{}
+)
+
+// Geography is a nullable GEOGRAPHY value, which can be used to scan GEOGRAPHY
+// columns (in any of the supported [GeographyFormat] formats), and to pass
+// geometries as GEOGRAPHY query parameters.
+type Geography struct {
+	Geometry Geometry
+	Valid    bool
+}
+
+func (g *Geography) Scan(src any) error {
+	var geometry Geometry
+	var err error
+	switch src := src.(type) {
+	case nil:
+		*g = Geography{}
+		return nil
+	case string:
+		geometry, err = ParseWKT(src)
+	case []byte:
+		geometry, err = parseGeographyBytes(src)
+	default:
+		return fmt.Errorf("cannot scan %T into Geography", src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*g = Geography{Geometry: geometry, Valid: true}
+	return nil
+}
+
+// Detects the format of a GEOGRAPHY value returned as a []byte value.
+func parseGeographyBytes(src []byte) (Geometry, error) {
+	trimmed := bytes.TrimSpace(src)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return ParseGeoJSON(trimmed)
+	case len(src) > 0 && (src[0] == 0 || src[0] == 1):
+		return ParseWKB(src)
+	default:
+		return ParseWKT(string(src))
+	}
+}
+
+// Value returns the WKT representation of the geometry.
+func (g Geography) Value() (driver.Value, error) {
+	if !g.Valid || g.Geometry == nil {
+		return nil, nil
+	}
+	return g.Geometry.WKT(), nil
+}
+
+// GeographyFormat specifies the representation used for GEOGRAPHY values
+// returned by the driver.
+type GeographyFormat string
+
+const (
+	// GeographyWKT returns GEOGRAPHY values as WKT strings (the default).
+	GeographyWKT GeographyFormat = "wkt"
+	// GeographyWKB returns GEOGRAPHY values as WKB []byte values.
+	GeographyWKB GeographyFormat = "wkb"
+	// GeographyGeoJSON returns GEOGRAPHY values as GeoJSON []byte values.
+	GeographyGeoJSON GeographyFormat = "geojson"
+)
+
+func parseGeographyFormat(format string) (GeographyFormat, error) {
+	switch f := GeographyFormat(strings.ToLower(format)); f {
+	case "", GeographyWKT, GeographyWKB, GeographyGeoJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid geography format: %s", format)
+	}
+}
+
+func convertGeographyType(field *bigquery.FieldSchema, value bigquery.Value, format GeographyFormat) (any, error) {
+	if format == "" || format == GeographyWKT {
+		return convertBasicType[string](field, value)
+	}
+
+	wkt, ok := value.(string)
+	if !ok {
+		return convertBasicType[string](field, value)
+	}
+	geometry, err := ParseWKT(wkt)
+	if err != nil {
+		return nil, err
+	}
+	if format == GeographyWKB {
+		return geometry.WKB(), nil
+	}
+	return geometry.GeoJSON(), nil
+}
+
+// A GEOGRAPHY query parameter, which is bound via a WKB (or, for NULL values,
+// a WKT) parameter wrapped in a conversion function.
+type geographyParam struct {
+	geography Geography
+}
+
+func checkGeographyValue(named *driver.NamedValue) bool {
+	switch value := named.Value.(type) {
+	case Geography:
+		named.Value = geographyParam{geography: value}
+		return true
+	case *Geography:
+		if value == nil {
+			named.Value = geographyParam{}
+		} else {
+			named.Value = geographyParam{geography: *value}
+		}
+		return true
+	case Geometry:
+		named.Value = geographyParam{geography: Geography{Geometry: value, Valid: true}}
+		return true
+	}
+	return false
+}
+
+// Wraps references to GEOGRAPHY parameters in the query in ST_GEOGFROMWKB (or
+// ST_GEOGFROMTEXT for NULL values), and replaces the parameter values with
+// values of the corresponding type.
+func bindGeographyParams(query string, args []driver.NamedValue) (string, []driver.NamedValue) {
+	wrappers := map[paramRef]string{}
+	var bound []driver.NamedValue
+	for i, arg := range args {
+		param, ok := arg.Value.(geographyParam)
+		if !ok {
+			continue
+		}
+		if bound == nil {
+			bound = append([]driver.NamedValue(nil), args...)
+		}
+
+		wrapper := "ST_GEOGFROMWKB"
+		if param.geography.Valid && param.geography.Geometry != nil {
+			bound[i].Value = param.geography.Geometry.WKB()
+		} else {
+			wrapper = "ST_GEOGFROMTEXT"
+			bound[i].Value = bigquery.NullString{}
+		}
+
+		if arg.Name != "" {
+			wrappers[paramRef{name: strings.ToLower(arg.Name)}] = wrapper
+		} else {
+			wrappers[paramRef{ordinal: i + 1}] = wrapper
+		}
+	}
+
+	if bound == nil {
+		return query, args
+	}
+
+	query = rewriteParams(query, func(ref paramRef) string {
+		wrapper, ok := wrappers[paramRef{name: strings.ToLower(ref.name), ordinal: ref.ordinal}]
+		if !ok {
+			return ref.text
+		}
+		return fmt.Sprintf("%s(%s)", wrapper, ref.text)
+	})
+	return query, bound
+}
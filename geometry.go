@@ -0,0 +1,842 @@
+package bigquery
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Geometry is an in-memory representation of a BigQuery GEOGRAPHY value. It is
+// implemented by the [Point], [LineString], [Polygon], [MultiPoint],
+// [MultiLineString], [MultiPolygon] and [GeometryCollection] types.
+type Geometry interface {
+	// WKT returns the Well-Known Text representation of the geometry.
+	WKT() string
+	// WKB returns the (little-endian) Well-Known Binary representation of the
+	// geometry.
+	WKB() []byte
+	// GeoJSON returns the GeoJSON representation of the geometry.
+	GeoJSON() []byte
+
+	wktType() string
+	appendWKTBody(b []byte) []byte
+	appendWKB(b []byte) []byte
+	geoJSON() geoJSONGeometry
+}
+
+// Point is a single location, where X is the longitude and Y is the latitude.
+type Point struct {
+	X, Y float64
+}
+
+// LineString is a sequence of points connected by edges.
+type LineString []Point
+
+// Polygon is a sequence of rings, the first of which is the exterior ring,
+// while any subsequent rings are holes.
+type Polygon []LineString
+
+// MultiPoint is a collection of points.
+type MultiPoint []Point
+
+// MultiLineString is a collection of line strings.
+type MultiLineString []LineString
+
+// MultiPolygon is a collection of polygons.
+type MultiPolygon []Polygon
+
+// GeometryCollection is a collection of arbitrary geometries. BigQuery
+// represents empty geographies as empty geometry collections.
+type GeometryCollection []Geometry
+
+var (
+	_ Geometry = Point{}
+	_ Geometry = LineString(nil)
+	_ Geometry = Polygon(nil)
+	_ Geometry = MultiPoint(nil)
+	_ Geometry = MultiLineString(nil)
+	_ Geometry = MultiPolygon(nil)
+	_ Geometry = GeometryCollection(nil)
+)
+
+// WKB geometry type codes.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+func (p Point) wktType() string              { return "POINT" }
+func (g LineString) wktType() string         { return "LINESTRING" }
+func (g Polygon) wktType() string            { return "POLYGON" }
+func (g MultiPoint) wktType() string         { return "MULTIPOINT" }
+func (g MultiLineString) wktType() string    { return "MULTILINESTRING" }
+func (g MultiPolygon) wktType() string       { return "MULTIPOLYGON" }
+func (g GeometryCollection) wktType() string { return "GEOMETRYCOLLECTION" }
+
+func (p Point) WKT() string              { return formatWKT(p) }
+func (g LineString) WKT() string         { return formatWKT(g) }
+func (g Polygon) WKT() string            { return formatWKT(g) }
+func (g MultiPoint) WKT() string         { return formatWKT(g) }
+func (g MultiLineString) WKT() string    { return formatWKT(g) }
+func (g MultiPolygon) WKT() string       { return formatWKT(g) }
+func (g GeometryCollection) WKT() string { return formatWKT(g) }
+
+func (p Point) WKB() []byte              { return p.appendWKB(nil) }
+func (g LineString) WKB() []byte         { return g.appendWKB(nil) }
+func (g Polygon) WKB() []byte            { return g.appendWKB(nil) }
+func (g MultiPoint) WKB() []byte         { return g.appendWKB(nil) }
+func (g MultiLineString) WKB() []byte    { return g.appendWKB(nil) }
+func (g MultiPolygon) WKB() []byte       { return g.appendWKB(nil) }
+func (g GeometryCollection) WKB() []byte { return g.appendWKB(nil) }
+
+func (p Point) GeoJSON() []byte              { return formatGeoJSON(p) }
+func (g LineString) GeoJSON() []byte         { return formatGeoJSON(g) }
+func (g Polygon) GeoJSON() []byte            { return formatGeoJSON(g) }
+func (g MultiPoint) GeoJSON() []byte         { return formatGeoJSON(g) }
+func (g MultiLineString) GeoJSON() []byte    { return formatGeoJSON(g) }
+func (g MultiPolygon) GeoJSON() []byte       { return formatGeoJSON(g) }
+func (g GeometryCollection) GeoJSON() []byte { return formatGeoJSON(g) }
+
+// WKT
+
+func formatWKT(g Geometry) string {
+	b := []byte(g.wktType())
+	return string(g.appendWKTBody(b))
+}
+
+func appendWKTCoords(b []byte, p Point) []byte {
+	b = strconv.AppendFloat(b, p.X, 'f', -1, 64)
+	b = append(b, ' ')
+	return strconv.AppendFloat(b, p.Y, 'f', -1, 64)
+}
+
+func appendWKTPoints(b []byte, points []Point) []byte {
+	if len(points) == 0 {
+		return append(b, " EMPTY"...)
+	}
+	b = append(b, '(')
+	for i, p := range points {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = appendWKTCoords(b, p)
+	}
+	return append(b, ')')
+}
+
+func appendWKTList[T any](b []byte, items []T, appendItem func([]byte, T) []byte) []byte {
+	if len(items) == 0 {
+		return append(b, " EMPTY"...)
+	}
+	b = append(b, '(')
+	for i, item := range items {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = appendItem(b, item)
+	}
+	return append(b, ')')
+}
+
+func (p Point) appendWKTBody(b []byte) []byte {
+	b = append(b, '(')
+	b = appendWKTCoords(b, p)
+	return append(b, ')')
+}
+
+func (g LineString) appendWKTBody(b []byte) []byte {
+	return appendWKTPoints(b, g)
+}
+
+func (g Polygon) appendWKTBody(b []byte) []byte {
+	return appendWKTList(b, g, func(b []byte, ring LineString) []byte {
+		return appendWKTPoints(b, ring)
+	})
+}
+
+func (g MultiPoint) appendWKTBody(b []byte) []byte {
+	return appendWKTPoints(b, g)
+}
+
+func (g MultiLineString) appendWKTBody(b []byte) []byte {
+	return appendWKTList(b, g, func(b []byte, line LineString) []byte {
+		return appendWKTPoints(b, line)
+	})
+}
+
+func (g MultiPolygon) appendWKTBody(b []byte) []byte {
+	return appendWKTList(b, g, func(b []byte, polygon Polygon) []byte {
+		return polygon.appendWKTBody(b)
+	})
+}
+
+func (g GeometryCollection) appendWKTBody(b []byte) []byte {
+	return appendWKTList(b, g, func(b []byte, geometry Geometry) []byte {
+		b = append(b, geometry.wktType()...)
+		return geometry.appendWKTBody(b)
+	})
+}
+
+// ParseWKT parses the Well-Known Text representation of a geometry, as
+// returned by BigQuery for GEOGRAPHY values.
+func ParseWKT(wkt string) (Geometry, error) {
+	p := &wktParser{input: wkt}
+	g, err := p.parseGeometry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid WKT: %w", err)
+	}
+	if p.skipSpace(); p.pos != len(p.input) {
+		return nil, fmt.Errorf("invalid WKT: unexpected trailing input at position %d", p.pos)
+	}
+	return g, nil
+}
+
+type wktParser struct {
+	input string
+	pos   int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *wktParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at position %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return strings.ToUpper(p.input[start:p.pos])
+}
+
+// Consumes the EMPTY keyword if present, or the opening parenthesis
+// otherwise. Reports whether the geometry is empty.
+func (p *wktParser) open() (bool, error) {
+	if p.peek() == '(' {
+		p.pos++
+		return false, nil
+	}
+	start := p.pos
+	if p.word() == "EMPTY" {
+		return true, nil
+	}
+	return false, fmt.Errorf("expected '(' or EMPTY at position %d", start)
+}
+
+// Parses a comma separated list of items, up to and including the closing
+// parenthesis (the opening parenthesis has already been consumed).
+func (p *wktParser) list(item func() error) error {
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() != ',' {
+			return p.expect(')')
+		}
+		p.pos++
+	}
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("+-.0123456789eE", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number at position %d", start)
+	}
+	return f, nil
+}
+
+func (p *wktParser) coords() (Point, error) {
+	x, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: x, Y: y}, nil
+}
+
+func (p *wktParser) points() ([]Point, error) {
+	empty, err := p.open()
+	if empty || err != nil {
+		return nil, err
+	}
+	var points []Point
+	err = p.list(func() error {
+		// MULTIPOINT members may optionally be enclosed in parentheses.
+		parens := p.peek() == '('
+		if parens {
+			p.pos++
+		}
+		point, err := p.coords()
+		if err != nil {
+			return err
+		}
+		points = append(points, point)
+		if parens {
+			return p.expect(')')
+		}
+		return nil
+	})
+	return points, err
+}
+
+func (p *wktParser) polygon() (Polygon, error) {
+	empty, err := p.open()
+	if empty || err != nil {
+		return nil, err
+	}
+	var polygon Polygon
+	err = p.list(func() error {
+		ring, err := p.points()
+		polygon = append(polygon, ring)
+		return err
+	})
+	return polygon, err
+}
+
+func (p *wktParser) parseGeometry() (Geometry, error) {
+	start := p.pos
+	switch typ := p.word(); typ {
+	case "POINT":
+		empty, err := p.open()
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			return GeometryCollection{}, nil
+		}
+		point, err := p.coords()
+		if err != nil {
+			return nil, err
+		}
+		return point, p.expect(')')
+	case "LINESTRING":
+		points, err := p.points()
+		return LineString(points), err
+	case "POLYGON":
+		return p.polygon()
+	case "MULTIPOINT":
+		points, err := p.points()
+		return MultiPoint(points), err
+	case "MULTILINESTRING":
+		empty, err := p.open()
+		if empty || err != nil {
+			return MultiLineString(nil), err
+		}
+		var lines MultiLineString
+		err = p.list(func() error {
+			line, err := p.points()
+			lines = append(lines, line)
+			return err
+		})
+		return lines, err
+	case "MULTIPOLYGON":
+		empty, err := p.open()
+		if empty || err != nil {
+			return MultiPolygon(nil), err
+		}
+		var polygons MultiPolygon
+		err = p.list(func() error {
+			polygon, err := p.polygon()
+			polygons = append(polygons, polygon)
+			return err
+		})
+		return polygons, err
+	case "GEOMETRYCOLLECTION":
+		empty, err := p.open()
+		if empty || err != nil {
+			return GeometryCollection{}, err
+		}
+		var geometries GeometryCollection
+		err = p.list(func() error {
+			geometry, err := p.parseGeometry()
+			geometries = append(geometries, geometry)
+			return err
+		})
+		return geometries, err
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q at position %d", typ, start)
+	}
+}
+
+// WKB
+
+func appendWKBHeader(b []byte, typ uint32) []byte {
+	b = append(b, 1) // Little-endian
+	return binary.LittleEndian.AppendUint32(b, typ)
+}
+
+func appendWKBPoints(b []byte, points []Point) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(points)))
+	for _, p := range points {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.X))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.Y))
+	}
+	return b
+}
+
+func (p Point) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbPoint)
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(p.X))
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(p.Y))
+}
+
+func (g LineString) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbLineString)
+	return appendWKBPoints(b, g)
+}
+
+func (g Polygon) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbPolygon)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(g)))
+	for _, ring := range g {
+		b = appendWKBPoints(b, ring)
+	}
+	return b
+}
+
+func (g MultiPoint) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbMultiPoint)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(g)))
+	for _, p := range g {
+		b = p.appendWKB(b)
+	}
+	return b
+}
+
+func (g MultiLineString) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbMultiLineString)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(g)))
+	for _, line := range g {
+		b = line.appendWKB(b)
+	}
+	return b
+}
+
+func (g MultiPolygon) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbMultiPolygon)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(g)))
+	for _, polygon := range g {
+		b = polygon.appendWKB(b)
+	}
+	return b
+}
+
+func (g GeometryCollection) appendWKB(b []byte) []byte {
+	b = appendWKBHeader(b, wkbGeometryCollection)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(g)))
+	for _, geometry := range g {
+		b = geometry.appendWKB(b)
+	}
+	return b
+}
+
+// ParseWKB parses the Well-Known Binary representation of a geometry (in
+// either byte order), as returned by the BigQuery ST_ASBINARY function.
+func ParseWKB(wkb []byte) (Geometry, error) {
+	p := &wkbParser{input: wkb}
+	g, err := p.parseGeometry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid WKB: %w", err)
+	}
+	if len(p.input) != 0 {
+		return nil, errors.New("invalid WKB: unexpected trailing input")
+	}
+	return g, nil
+}
+
+type wkbParser struct {
+	input []byte
+	order binary.ByteOrder
+}
+
+var errWKBTooShort = errors.New("unexpected end of input")
+
+func (p *wkbParser) uint32() (uint32, error) {
+	if len(p.input) < 4 {
+		return 0, errWKBTooShort
+	}
+	v := p.order.Uint32(p.input)
+	p.input = p.input[4:]
+	return v, nil
+}
+
+func (p *wkbParser) count() (int, error) {
+	n, err := p.uint32()
+	if err != nil {
+		return 0, err
+	}
+	// Every item takes up at least 4 bytes, which prevents huge allocations
+	// for invalid input.
+	if int(n) > len(p.input)/4 {
+		return 0, errWKBTooShort
+	}
+	return int(n), nil
+}
+
+func (p *wkbParser) coords() (Point, error) {
+	if len(p.input) < 16 {
+		return Point{}, errWKBTooShort
+	}
+	x := math.Float64frombits(p.order.Uint64(p.input))
+	y := math.Float64frombits(p.order.Uint64(p.input[8:]))
+	p.input = p.input[16:]
+	return Point{X: x, Y: y}, nil
+}
+
+func (p *wkbParser) points() ([]Point, error) {
+	n, err := p.count()
+	if err != nil {
+		return nil, err
+	}
+	points := make([]Point, n)
+	for i := range points {
+		if points[i], err = p.coords(); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (p *wkbParser) header() (uint32, error) {
+	if len(p.input) < 1 {
+		return 0, errWKBTooShort
+	}
+	switch p.input[0] {
+	case 0:
+		p.order = binary.BigEndian
+	case 1:
+		p.order = binary.LittleEndian
+	default:
+		return 0, fmt.Errorf("invalid byte order: %d", p.input[0])
+	}
+	p.input = p.input[1:]
+	return p.uint32()
+}
+
+// Parses a geometry of the given type, which is a member of a multi-geometry.
+func (p *wkbParser) member(typ uint32) (Geometry, error) {
+	g, err := p.parseGeometry()
+	if err != nil {
+		return nil, err
+	}
+	if actual := wkbType(g); actual != typ {
+		return nil, fmt.Errorf("unexpected geometry type %d in collection of type %d", actual, typ)
+	}
+	return g, nil
+}
+
+func wkbType(g Geometry) uint32 {
+	switch g.(type) {
+	case Point:
+		return wkbPoint
+	case LineString:
+		return wkbLineString
+	case Polygon:
+		return wkbPolygon
+	case MultiPoint:
+		return wkbMultiPoint
+	case MultiLineString:
+		return wkbMultiLineString
+	case MultiPolygon:
+		return wkbMultiPolygon
+	default:
+		return wkbGeometryCollection
+	}
+}
+
+func (p *wkbParser) parseGeometry() (Geometry, error) {
+	typ, err := p.header()
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case wkbPoint:
+		point, err := p.coords()
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(point.X) && math.IsNaN(point.Y) {
+			return GeometryCollection{}, nil
+		}
+		return point, nil
+	case wkbLineString:
+		points, err := p.points()
+		return LineString(points), err
+	case wkbPolygon:
+		n, err := p.count()
+		if err != nil {
+			return nil, err
+		}
+		polygon := make(Polygon, n)
+		for i := range polygon {
+			if polygon[i], err = p.points(); err != nil {
+				return nil, err
+			}
+		}
+		return polygon, nil
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		n, err := p.count()
+		if err != nil {
+			return nil, err
+		}
+		members := make([]Geometry, n)
+		for i := range members {
+			if typ == wkbGeometryCollection {
+				members[i], err = p.parseGeometry()
+			} else {
+				// Multi-geometry types are offset by 3 from their member types.
+				members[i], err = p.member(typ - 3)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return collect(typ, members), nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type: %d", typ)
+	}
+}
+
+// Converts the members of a multi-geometry to the corresponding type.
+func collect(typ uint32, members []Geometry) Geometry {
+	switch typ {
+	case wkbMultiPoint:
+		g := make(MultiPoint, len(members))
+		for i, m := range members {
+			g[i] = m.(Point)
+		}
+		return g
+	case wkbMultiLineString:
+		g := make(MultiLineString, len(members))
+		for i, m := range members {
+			g[i] = m.(LineString)
+		}
+		return g
+	case wkbMultiPolygon:
+		g := make(MultiPolygon, len(members))
+		for i, m := range members {
+			g[i] = m.(Polygon)
+		}
+		return g
+	default:
+		return GeometryCollection(members)
+	}
+}
+
+// GeoJSON
+
+type geoJSONGeometry struct {
+	Type        string             `json:"type"`
+	Coordinates any                `json:"coordinates,omitempty"`
+	Geometries  *[]geoJSONGeometry `json:"geometries,omitempty"`
+}
+
+func formatGeoJSON(g Geometry) []byte {
+	// Marshalling can't fail, since the GeoJSON types only contain slices of
+	// floats (except for NaN/Inf, which are not valid coordinates anyway).
+	out, _ := json.Marshal(g.geoJSON())
+	return out
+}
+
+func geoJSONPoints(points []Point) [][2]float64 {
+	coords := make([][2]float64, len(points))
+	for i, p := range points {
+		coords[i] = [2]float64{p.X, p.Y}
+	}
+	return coords
+}
+
+func geoJSONPolygon(polygon Polygon) [][][2]float64 {
+	coords := make([][][2]float64, len(polygon))
+	for i, ring := range polygon {
+		coords[i] = geoJSONPoints(ring)
+	}
+	return coords
+}
+
+func (p Point) geoJSON() geoJSONGeometry {
+	return geoJSONGeometry{Type: "Point", Coordinates: [2]float64{p.X, p.Y}}
+}
+
+func (g LineString) geoJSON() geoJSONGeometry {
+	return geoJSONGeometry{Type: "LineString", Coordinates: geoJSONPoints(g)}
+}
+
+func (g Polygon) geoJSON() geoJSONGeometry {
+	return geoJSONGeometry{Type: "Polygon", Coordinates: geoJSONPolygon(g)}
+}
+
+func (g MultiPoint) geoJSON() geoJSONGeometry {
+	return geoJSONGeometry{Type: "MultiPoint", Coordinates: geoJSONPoints(g)}
+}
+
+func (g MultiLineString) geoJSON() geoJSONGeometry {
+	coords := make([][][2]float64, len(g))
+	for i, line := range g {
+		coords[i] = geoJSONPoints(line)
+	}
+	return geoJSONGeometry{Type: "MultiLineString", Coordinates: coords}
+}
+
+func (g MultiPolygon) geoJSON() geoJSONGeometry {
+	coords := make([][][][2]float64, len(g))
+	for i, polygon := range g {
+		coords[i] = geoJSONPolygon(polygon)
+	}
+	return geoJSONGeometry{Type: "MultiPolygon", Coordinates: coords}
+}
+
+func (g GeometryCollection) geoJSON() geoJSONGeometry {
+	geometries := make([]geoJSONGeometry, len(g))
+	for i, geometry := range g {
+		geometries[i] = geometry.geoJSON()
+	}
+	return geoJSONGeometry{Type: "GeometryCollection", Geometries: &geometries}
+}
+
+// ParseGeoJSON parses a GeoJSON geometry object, as returned by the BigQuery
+// ST_ASGEOJSON function.
+func ParseGeoJSON(data []byte) (Geometry, error) {
+	g, err := parseGeoJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	return g, nil
+}
+
+func parseGeoJSON(data []byte) (Geometry, error) {
+	var obj struct {
+		Type        string            `json:"type"`
+		Coordinates json.RawMessage   `json:"coordinates"`
+		Geometries  []json.RawMessage `json:"geometries"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	switch obj.Type {
+	case "Point":
+		var c []float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		if len(c) == 0 {
+			return GeometryCollection{}, nil
+		}
+		if len(c) < 2 {
+			return nil, errors.New("point must have at least two coordinates")
+		}
+		return Point{X: c[0], Y: c[1]}, nil
+	case "LineString":
+		var c [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		points, err := pointsFromGeoJSON(c)
+		return LineString(points), err
+	case "Polygon":
+		var c [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		return polygonFromGeoJSON(c)
+	case "MultiPoint":
+		var c [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		points, err := pointsFromGeoJSON(c)
+		return MultiPoint(points), err
+	case "MultiLineString":
+		var c [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		polygon, err := polygonFromGeoJSON(c)
+		return MultiLineString(polygon), err
+	case "MultiPolygon":
+		var c [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return nil, err
+		}
+		g := make(MultiPolygon, len(c))
+		for i, polygon := range c {
+			var err error
+			if g[i], err = polygonFromGeoJSON(polygon); err != nil {
+				return nil, err
+			}
+		}
+		return g, nil
+	case "GeometryCollection":
+		g := make(GeometryCollection, len(obj.Geometries))
+		for i, raw := range obj.Geometries {
+			geometry, err := parseGeoJSON(raw)
+			if err != nil {
+				return nil, err
+			}
+			g[i] = geometry
+		}
+		return g, nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type: %q", obj.Type)
+	}
+}
+
+// Converts GeoJSON positions to points, ignoring any altitude.
+func pointsFromGeoJSON(coords [][]float64) ([]Point, error) {
+	points := make([]Point, len(coords))
+	for i, c := range coords {
+		if len(c) < 2 {
+			return nil, errors.New("position must have at least two coordinates")
+		}
+		points[i] = Point{X: c[0], Y: c[1]}
+	}
+	return points, nil
+}
+
+func polygonFromGeoJSON(coords [][][]float64) (Polygon, error) {
+	polygon := make(Polygon, len(coords))
+	for i, ring := range coords {
+		var err error
+		if polygon[i], err = pointsFromGeoJSON(ring); err != nil {
+			return nil, err
+		}
+	}
+	return polygon, nil
+}
@@ -0,0 +1,238 @@
+package bigquery
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestGeometryRoundTrip(t *testing.T) {
+	for _, wkt := range []string{
+		"POINT(1 2)",
+		"POINT(-122.4194 37.7749)",
+		"LINESTRING(0 0, 1.5 -2)",
+		"LINESTRING EMPTY",
+		"POLYGON((0 0, 1 0, 1 1, 0 0), (0.2 0.2, 0.4 0.2, 0.4 0.4, 0.2 0.2))",
+		"POLYGON EMPTY",
+		"MULTIPOINT(1 2, 3 4)",
+		"MULTIPOINT EMPTY",
+		"MULTILINESTRING((0 0, 1 1), (2 2, 3 3))",
+		"MULTILINESTRING EMPTY",
+		"MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0)), ((2 2, 3 2, 3 3, 2 2)))",
+		"MULTIPOLYGON EMPTY",
+		"GEOMETRYCOLLECTION(POINT(1 2), GEOMETRYCOLLECTION(LINESTRING(0 0, 1 1), GEOMETRYCOLLECTION EMPTY))",
+		"GEOMETRYCOLLECTION EMPTY",
+	} {
+		g, err := ParseWKT(wkt)
+		if err != nil {
+			t.Errorf("ParseWKT(%q): %v", wkt, err)
+			continue
+		}
+		if got := g.WKT(); got != wkt {
+			t.Errorf("ParseWKT(%q).WKT() = %q", wkt, got)
+		}
+
+		fromWKB, err := ParseWKB(g.WKB())
+		if err != nil {
+			t.Errorf("ParseWKB(%q as WKB): %v", wkt, err)
+		} else if got := fromWKB.WKT(); got != wkt {
+			t.Errorf("ParseWKB(%q as WKB).WKT() = %q", wkt, got)
+		}
+
+		fromGeoJSON, err := ParseGeoJSON(g.GeoJSON())
+		if err != nil {
+			t.Errorf("ParseGeoJSON(%s): %v", g.GeoJSON(), err)
+		} else if got := fromGeoJSON.WKT(); got != wkt {
+			t.Errorf("ParseGeoJSON(%s).WKT() = %q, want %q", g.GeoJSON(), got, wkt)
+		}
+	}
+}
+
+func TestParseWKTVariants(t *testing.T) {
+	tests := []struct {
+		wkt  string
+		want string
+	}{
+		// MULTIPOINT members may or may not be enclosed in parentheses.
+		{"MULTIPOINT((1 2), (3 4))", "MULTIPOINT(1 2, 3 4)"},
+		{"MULTIPOINT((1 2), 3 4)", "MULTIPOINT(1 2, 3 4)"},
+		{" point ( 1\t2 ) ", "POINT(1 2)"},
+		{"LineString (1e2 -2.5E-1, +3 .5)", "LINESTRING(100 -0.25, 3 0.5)"},
+		// BigQuery represents empty geographies as empty collections.
+		{"POINT EMPTY", "GEOMETRYCOLLECTION EMPTY"},
+		{"GEOMETRYCOLLECTION(POINT EMPTY)", "GEOMETRYCOLLECTION(GEOMETRYCOLLECTION EMPTY)"},
+	}
+	for _, test := range tests {
+		g, err := ParseWKT(test.wkt)
+		if err != nil {
+			t.Errorf("ParseWKT(%q): %v", test.wkt, err)
+			continue
+		}
+		if got := g.WKT(); got != test.want {
+			t.Errorf("ParseWKT(%q).WKT() = %q, want %q", test.wkt, got, test.want)
+		}
+	}
+}
+
+func TestParseWKTInvalid(t *testing.T) {
+	for _, wkt := range []string{
+		"",
+		"POINT",
+		"POINT()",
+		"POINT(1)",
+		"POINT(1 2",
+		"POINT(a b)",
+		"POINT(1 2) x",
+		"POINT(1 2))",
+		"LINESTRING(0 0,)",
+		"LINESTRING(0 0 1 1)",
+		"POLYGON(0 0, 1 1)",
+		"MULTIPOINT((1 2)",
+		"MULTIPOLYGON((0 0, 1 1))",
+		"GEOMETRYCOLLECTION(POINT(1 2), )",
+		"GEOMETRYCOLLECTION(CIRCLE(1 2))",
+		"CIRCLE(1 2)",
+	} {
+		if g, err := ParseWKT(wkt); err == nil {
+			t.Errorf("ParseWKT(%q) = %v, want an error", wkt, g.WKT())
+		}
+	}
+}
+
+// Builds WKB in the given byte order from a sequence of byte order markers
+// (bytes), types and counts (uint32) and coordinates (float64).
+func buildWKB(order binary.AppendByteOrder, parts ...any) []byte {
+	var b []byte
+	for _, part := range parts {
+		switch v := part.(type) {
+		case byte:
+			b = append(b, v)
+		case uint32:
+			b = order.AppendUint32(b, v)
+		case float64:
+			b = order.AppendUint64(b, math.Float64bits(v))
+		}
+	}
+	return b
+}
+
+func TestParseWKB(t *testing.T) {
+	be, le := binary.BigEndian, binary.LittleEndian
+	tests := []struct {
+		name string
+		wkb  []byte
+		want string
+	}{
+		{"big-endian point", buildWKB(be, byte(0), uint32(1), 1.0, 2.0), "POINT(1 2)"},
+		{
+			"big-endian line string",
+			buildWKB(be, byte(0), uint32(2), uint32(2), 0.0, 0.0, 1.5, -2.0),
+			"LINESTRING(0 0, 1.5 -2)",
+		},
+		{
+			"mixed byte orders",
+			append(
+				buildWKB(be, byte(0), uint32(4), uint32(2), byte(0), uint32(1), 1.0, 2.0),
+				buildWKB(le, byte(1), uint32(1), 3.0, 4.0)...,
+			),
+			"MULTIPOINT(1 2, 3 4)",
+		},
+		{"empty point", buildWKB(le, byte(1), uint32(1), math.NaN(), math.NaN()), "GEOMETRYCOLLECTION EMPTY"},
+		{"big-endian empty point", buildWKB(be, byte(0), uint32(1), math.NaN(), math.NaN()), "GEOMETRYCOLLECTION EMPTY"},
+		{
+			"nested collections",
+			buildWKB(le,
+				byte(1), uint32(7), uint32(2),
+				byte(1), uint32(1), math.NaN(), math.NaN(),
+				byte(1), uint32(7), uint32(1),
+				byte(1), uint32(2), uint32(2), 0.0, 0.0, 1.0, 1.0,
+			),
+			"GEOMETRYCOLLECTION(GEOMETRYCOLLECTION EMPTY, GEOMETRYCOLLECTION(LINESTRING(0 0, 1 1)))",
+		},
+	}
+	for _, test := range tests {
+		g, err := ParseWKB(test.wkb)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := g.WKT(); got != test.want {
+			t.Errorf("%s: WKT() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseWKBInvalid(t *testing.T) {
+	le := binary.LittleEndian
+	g, err := ParseWKT("GEOMETRYCOLLECTION(POINT(1 2), MULTIPOLYGON(((0 0, 1 0, 1 1, 0 0))), MULTILINESTRING((0 0, 1 1)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wkb := g.WKB()
+
+	// Every truncation of valid WKB is invalid.
+	for n := range len(wkb) {
+		if g, err := ParseWKB(wkb[:n]); err == nil {
+			t.Errorf("ParseWKB(%x) = %v, want an error", wkb[:n], g.WKT())
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		wkb  []byte
+	}{
+		{"trailing garbage", append(g.WKB(), 0)},
+		{"invalid byte order", buildWKB(le, byte(2), uint32(1), 1.0, 2.0)},
+		{"unsupported type", buildWKB(le, byte(1), uint32(17), 1.0, 2.0)},
+		{"3D point", buildWKB(le, byte(1), uint32(1001), 1.0, 2.0, 3.0)},
+		{"huge count", buildWKB(le, byte(1), uint32(2), uint32(math.MaxUint32))},
+		{"mismatched member", buildWKB(le, byte(1), uint32(4), uint32(1), byte(1), uint32(2), uint32(0))},
+	} {
+		if g, err := ParseWKB(test.wkb); err == nil {
+			t.Errorf("%s: ParseWKB(%x) = %v, want an error", test.name, test.wkb, g.WKT())
+		}
+	}
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	tests := []struct {
+		geoJSON string
+		want    string
+	}{
+		{`{"type":"Point","coordinates":[1,2,3]}`, "POINT(1 2)"},
+		{`{"type":"Point","coordinates":[]}`, "GEOMETRYCOLLECTION EMPTY"},
+		{`{"type":"MultiPoint","coordinates":[]}`, "MULTIPOINT EMPTY"},
+		{`{"type":"LineString","coordinates":[[0,0],[1,1,5]]}`, "LINESTRING(0 0, 1 1)"},
+		{`{"type":"GeometryCollection","geometries":[]}`, "GEOMETRYCOLLECTION EMPTY"},
+	}
+	for _, test := range tests {
+		g, err := ParseGeoJSON([]byte(test.geoJSON))
+		if err != nil {
+			t.Errorf("ParseGeoJSON(%s): %v", test.geoJSON, err)
+			continue
+		}
+		if got := g.WKT(); got != test.want {
+			t.Errorf("ParseGeoJSON(%s).WKT() = %q, want %q", test.geoJSON, got, test.want)
+		}
+	}
+}
+
+func TestParseGeoJSONInvalid(t *testing.T) {
+	for _, geoJSON := range []string{
+		``,
+		`{`,
+		`{"type":"Circle","coordinates":[1,2]}`,
+		`{"type":"Point","coordinates":[1]}`,
+		`{"type":"Point"}`,
+		`{"type":"LineString","coordinates":"x"}`,
+		`{"type":"LineString","coordinates":[[0,0],[1]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[1,0],[1]]]}`,
+		`{"type":"MultiPolygon","coordinates":[[[0,0]]]}`,
+		`{"type":"GeometryCollection","geometries":[{"type":"Circle"}]}`,
+		`{"type":"Point","coordinates":[1,2]} x`,
+	} {
+		if g, err := ParseGeoJSON([]byte(geoJSON)); err == nil {
+			t.Errorf("ParseGeoJSON(%s) = %v, want an error", geoJSON, g.WKT())
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+var (
+	_ gorm.ConnPool         = connPool{}
+	_ gorm.ConnPoolBeginner = connPool{}
+	_ gorm.GetDBConnector   = connPool{}
+	_ gorm.TxCommitter      = (*txConnPool)(nil)
+)
+
+// Wraps a connection pool (or a connection or transaction), and passes the
+// arguments of statements as the named parameters @p1, @p2, etc. written by
+// [Dialector.BindVarTo]. GORM itself keeps the arguments positional, since it
+// rebinds the arguments of subqueries and joins.
+type connPool struct {
+	gorm.ConnPool
+}
+
+func (p connPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return p.ConnPool.ExecContext(ctx, query, namedArgs(args)...)
+}
+
+func (p connPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return p.ConnPool.QueryContext(ctx, query, namedArgs(args)...)
+}
+
+func (p connPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return p.ConnPool.QueryRowContext(ctx, query, namedArgs(args)...)
+}
+
+func (p connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &txConnPool{connPool: connPool{tx}, tx: tx}, nil
+	case gorm.ConnPoolBeginner:
+		pool, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		committer, ok := pool.(gorm.TxCommitter)
+		if !ok {
+			return nil, gorm.ErrInvalidTransaction
+		}
+		return &txConnPool{connPool: connPool{pool}, tx: committer}, nil
+	}
+	return nil, gorm.ErrInvalidTransaction
+}
+
+// GetDBConn returns the wrapped *sql.DB (e.g. for [gorm.DB.DB]).
+func (p connPool) GetDBConn() (*sql.DB, error) {
+	switch pool := p.ConnPool.(type) {
+	case *sql.DB:
+		return pool, nil
+	case gorm.GetDBConnector:
+		return pool.GetDBConn()
+	}
+	return nil, nil
+}
+
+// A wrapped transaction (which GORM requires to be a pointer).
+type txConnPool struct {
+	connPool
+	tx gorm.TxCommitter
+}
+
+func (p *txConnPool) Commit() error {
+	return p.tx.Commit()
+}
+
+func (p *txConnPool) Rollback() error {
+	return p.tx.Rollback()
+}
+
+// Names the arguments p1, p2, etc., in order, omitting the null ones (which
+// [Dialector.BindVarTo] writes as NULL).
+func namedArgs(args []any) []any {
+	named := make([]any, 0, len(args))
+	for i, arg := range args {
+		if !isNull(arg) {
+			named = append(named, sql.Named(bindVarName(i+1), arg))
+		}
+	}
+	return named
+}
+
+// Reports whether the argument is passed as NULL (e.g. nil, a nil pointer, or
+// an invalid sql.NullString).
+func isNull(arg any) bool {
+	if arg == nil {
+		return true
+	}
+	if v := reflect.ValueOf(arg); v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if valuer, ok := arg.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		return err == nil && value == nil
+	}
+	return false
+}
+
+// Returns the name of the parameter with the given (1-based) position.
+func bindVarName(position int) string {
+	return "p" + strconv.Itoa(position)
+}
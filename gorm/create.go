@@ -0,0 +1,185 @@
+package gorm
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+)
+
+// The create callback, which runs inserts with an ON CONFLICT clause as MERGE
+// statements. Unlike GORM's callback, it doesn't read back auto-incremented
+// primary keys (which BigQuery doesn't have) via LastInsertId.
+func create(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	stmt := db.Statement
+	if stmt.Schema != nil && !stmt.Unscoped {
+		for _, c := range stmt.Schema.CreateClauses {
+			stmt.AddClause(c)
+		}
+	}
+
+	if stmt.SQL.Len() == 0 {
+		if _, ok := stmt.Clauses["ON CONFLICT"]; ok {
+			buildMerge(stmt)
+		} else {
+			stmt.AddClauseIfNotExists(clause.Insert{})
+			stmt.AddClause(callbacks.ConvertToCreateValues(stmt))
+			stmt.Build(stmt.BuildClauses...)
+		}
+	}
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	result, err := stmt.ConnPool.ExecContext(stmt.Context, stmt.SQL.String(), stmt.Vars...)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.RowsAffected, _ = result.RowsAffected()
+	if stmt.Result != nil {
+		stmt.Result.Result = result
+		stmt.Result.RowsAffected = db.RowsAffected
+	}
+}
+
+// Builds a MERGE statement of the form:
+//
+//	MERGE INTO `table` AS target
+//	USING (SELECT ? AS `a`, ? AS `b` UNION ALL SELECT ?, ?) AS source
+//	ON target.`a` = source.`a`
+//	WHEN MATCHED THEN UPDATE SET `b` = source.`b`
+//	WHEN NOT MATCHED THEN INSERT (`a`, `b`) VALUES (source.`a`, source.`b`)
+//
+// The conflict columns default to the primary key (as resolved by GORM).
+// Without any, rows are always inserted.
+func buildMerge(stmt *gorm.Statement) {
+	values := callbacks.ConvertToCreateValues(stmt)
+	if stmt.Error != nil {
+		return
+	}
+	// Converting the values resolves UpdateAll into the columns to update.
+	onConflict, _ := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict)
+
+	columns, rows, err := omitDefaults(values)
+	if err != nil {
+		stmt.AddError(err)
+		return
+	}
+
+	stmt.WriteString("MERGE INTO ")
+	stmt.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+	stmt.WriteString(" AS target USING (")
+	for i, row := range rows {
+		if i > 0 {
+			stmt.WriteString(" UNION ALL ")
+		}
+		stmt.WriteString("SELECT ")
+		for j, value := range row {
+			if j > 0 {
+				stmt.WriteString(", ")
+			}
+			stmt.AddVar(stmt, value)
+			if i == 0 {
+				stmt.WriteString(" AS ")
+				stmt.WriteQuoted(columns[j].Name)
+			}
+		}
+	}
+
+	stmt.WriteString(") AS source ON ")
+	if len(onConflict.Columns) == 0 {
+		stmt.WriteString("FALSE")
+	}
+	for i, column := range onConflict.Columns {
+		if i > 0 {
+			stmt.WriteString(" AND ")
+		}
+		stmt.WriteQuoted(clause.Column{Table: "target", Name: column.Name})
+		stmt.WriteString(" = ")
+		stmt.WriteQuoted(clause.Column{Table: "source", Name: column.Name})
+	}
+
+	if !onConflict.DoNothing && len(onConflict.DoUpdates) > 0 {
+		stmt.WriteString(" WHEN MATCHED")
+		if len(onConflict.Where.Exprs) > 0 {
+			stmt.WriteString(" AND ")
+			onConflict.Where.Build(stmt)
+		}
+		stmt.WriteString(" THEN UPDATE SET ")
+		for i, assignment := range onConflict.DoUpdates {
+			if i > 0 {
+				stmt.WriteString(", ")
+			}
+			stmt.WriteQuoted(assignment.Column.Name)
+			stmt.WriteString(" = ")
+			// Assignments from excluded columns (e.g. via UpdateAll or
+			// clause.AssignmentColumns) refer to the source row.
+			if column, ok := assignment.Value.(clause.Column); ok && column.Table == "excluded" {
+				stmt.WriteQuoted(clause.Column{Table: "source", Name: column.Name})
+			} else {
+				stmt.AddVar(stmt, assignment.Value)
+			}
+		}
+	}
+
+	stmt.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	for i, column := range columns {
+		if i > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteQuoted(column.Name)
+	}
+	stmt.WriteString(") VALUES (")
+	for i, column := range columns {
+		if i > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteQuoted(clause.Column{Table: "source", Name: column.Name})
+	}
+	stmt.WriteString(")")
+}
+
+// Omits the columns for which every row has the DEFAULT value, so that the
+// MERGE statement inserts their default values (which can't be selected from
+// the source rows).
+func omitDefaults(values clause.Values) ([]clause.Column, [][]any, error) {
+	var keep []int
+	for i, column := range values.Columns {
+		defaults := 0
+		for _, row := range values.Values {
+			if isDefault(row[i]) {
+				defaults++
+			}
+		}
+		switch defaults {
+		case 0:
+			keep = append(keep, i)
+		case len(values.Values):
+		default:
+			return nil, nil, fmt.Errorf("can't upsert rows with both default and non-default values for column %s", column.Name)
+		}
+	}
+
+	columns := make([]clause.Column, len(keep))
+	for i, index := range keep {
+		columns[i] = values.Columns[index]
+	}
+	rows := make([][]any, len(values.Values))
+	for i, row := range values.Values {
+		rows[i] = make([]any, len(keep))
+		for j, index := range keep {
+			rows[i][j] = row[index]
+		}
+	}
+	return columns, rows, nil
+}
+
+func isDefault(value any) bool {
+	expr, ok := value.(clause.Expr)
+	return ok && expr.SQL == "DEFAULT" && len(expr.Vars) == 0
+}
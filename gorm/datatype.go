@@ -0,0 +1,142 @@
+package gorm
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/civil"
+	bigquery "github.com/timescale/bigquery-go-client"
+	"gorm.io/gorm/schema"
+)
+
+// DataTypeOf returns the column type of a field. Besides GORM's generic types,
+// fields may be tagged with a BigQuery type (e.g. `gorm:"type:GEOGRAPHY"`), in
+// which case ARRAY, STRUCT, NUMERIC and BIGNUMERIC are completed from the
+// field's Go type (and its precision and scale, for NUMERIC and BIGNUMERIC),
+// e.g. a []string field tagged with `gorm:"type:ARRAY"` is an
+// ARRAY<STRING> column. The fields of STRUCT columns are named after their
+// `bigquery:"name"` tags, falling back to the Go field names.
+func (d *Dialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "BOOL"
+	case schema.Int, schema.Uint:
+		return "INT64"
+	case schema.Float:
+		return "FLOAT64"
+	case schema.String:
+		if field.Size > 0 {
+			return fmt.Sprintf("STRING(%d)", field.Size)
+		}
+		return "STRING"
+	case schema.Bytes:
+		if field.Size > 0 {
+			return fmt.Sprintf("BYTES(%d)", field.Size)
+		}
+		return "BYTES"
+	case schema.Time:
+		return "TIMESTAMP"
+	}
+
+	switch dataType := strings.ToUpper(string(field.DataType)); dataType {
+	case "", "ARRAY", "STRUCT":
+		return typeOf(field.FieldType)
+	case "NUMERIC", "BIGNUMERIC":
+		switch {
+		case field.Precision > 0 && field.Scale > 0:
+			return fmt.Sprintf("%s(%d, %d)", dataType, field.Precision, field.Scale)
+		case field.Precision > 0:
+			return fmt.Sprintf("%s(%d)", dataType, field.Precision)
+		}
+		return dataType
+	}
+	return string(field.DataType)
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	dateType       = reflect.TypeFor[civil.Date]()
+	civilTimeType  = reflect.TypeFor[civil.Time]()
+	dateTimeType   = reflect.TypeFor[civil.DateTime]()
+	ratType        = reflect.TypeFor[big.Rat]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	geographyType  = reflect.TypeFor[bigquery.Geography]()
+)
+
+// Returns the BigQuery type of a Go type, as used for query parameters.
+func typeOf(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return "TIMESTAMP"
+	case dateType:
+		return "DATE"
+	case civilTimeType:
+		return "TIME"
+	case dateTimeType:
+		return "DATETIME"
+	case ratType:
+		return "NUMERIC"
+	case rawMessageType:
+		return "JSON"
+	case geographyType:
+		return "GEOGRAPHY"
+	}
+
+	// The generic JSON and Range types of the driver.
+	if t.PkgPath() == geographyType.PkgPath() {
+		switch {
+		case strings.HasPrefix(t.Name(), "JSON["):
+			return "JSON"
+		case strings.HasPrefix(t.Name(), "Range["):
+			start, _ := t.FieldByName("Start")
+			return "RANGE<" + typeOf(start.Type) + ">"
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOL"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INT64"
+	case reflect.Float32, reflect.Float64:
+		return "FLOAT64"
+	case reflect.String:
+		return "STRING"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "BYTES"
+		}
+		return "ARRAY<" + typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		return structTypeOf(t)
+	case reflect.Map, reflect.Interface:
+		return "JSON"
+	}
+	return "STRING"
+}
+
+func structTypeOf(t reflect.Type) string {
+	var fields []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("bigquery")
+		if tag == "-" || !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, "`"+name+"` "+typeOf(f.Type))
+	}
+	return "STRUCT<" + strings.Join(fields, ", ") + ">"
+}
@@ -0,0 +1,151 @@
+// Package gorm implements a [gorm.Dialector] for BigQuery, on top of the
+// database/sql driver of the parent package.
+package gorm
+
+import (
+	"database/sql"
+	"math"
+	"regexp"
+	"strings"
+
+	bigquery "github.com/timescale/bigquery-go-client"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	_ gorm.Dialector = (*Dialector)(nil)
+)
+
+// Dialector is a [gorm.Dialector] for BigQuery.
+type Dialector struct {
+	// DSN, if set, is parsed (with [bigquery.ParseDSN]) into Config when the
+	// dialector is initialized.
+	DSN string
+
+	// Config is used to open a connector (unless Conn is set), and determines
+	// the dataset which the migrator operates on.
+	Config bigquery.Config
+
+	// Conn, if set, is used instead of opening a new connector (e.g. to share
+	// an existing *sql.DB). Config.Dataset should then be set to its dataset
+	// as well, for the migrator.
+	Conn gorm.ConnPool
+}
+
+// Open returns a dialector which connects using the given DSN (see the
+// parent package for its format).
+func Open(dsn string) gorm.Dialector {
+	return &Dialector{DSN: dsn}
+}
+
+// New returns a dialector which connects using the given config.
+func New(config bigquery.Config) gorm.Dialector {
+	return &Dialector{Config: config}
+}
+
+func (d *Dialector) Name() string {
+	return "bigquery"
+}
+
+func (d *Dialector) Initialize(db *gorm.DB) error {
+	if d.DSN != "" {
+		config, err := bigquery.ParseDSN(d.DSN)
+		if err != nil {
+			return err
+		}
+		d.Config = config
+	}
+
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{
+		CreateClauses: []string{"INSERT", "VALUES"},
+	})
+	// Upserts are run as MERGE statements, as BigQuery has no ON CONFLICT, and
+	// no IDs are read back, as BigQuery has no auto-incrementing columns.
+	if err := db.Callback().Create().Replace("gorm:create", create); err != nil {
+		return err
+	}
+
+	db.ClauseBuilders["LIMIT"] = buildLimit
+
+	if d.Conn != nil {
+		db.ConnPool = connPool{d.Conn}
+	} else {
+		db.ConnPool = connPool{sql.OpenDB(bigquery.NewConnector(d.Config))}
+	}
+	return nil
+}
+
+func (d *Dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return Migrator{
+		Migrator: migrator.Migrator{
+			Config: migrator.Config{
+				DB:        db,
+				Dialector: d,
+			},
+		},
+		dialector: d,
+	}
+}
+
+func (d *Dialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+// BindVarTo writes named parameters (@p1, @p2, etc., numbered by the position
+// of their arguments), which are named accordingly when the statement is run.
+// Null values are written as NULL instead, as BigQuery parameters are typed.
+func (d *Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v any) {
+	if isNull(v) {
+		writer.WriteString("NULL")
+		return
+	}
+	writer.WriteByte('@')
+	writer.WriteString(bindVarName(len(stmt.Vars)))
+}
+
+// QuoteTo quotes each part of a (possibly qualified) name with backticks,
+// e.g. project.dataset.table becomes `project`.`dataset`.`table`.
+func (d *Dialector) QuoteTo(writer clause.Writer, str string) {
+	for i, part := range strings.Split(str, ".") {
+		if i > 0 {
+			writer.WriteByte('.')
+		}
+		writer.WriteByte('`')
+		writer.WriteString(strings.ReplaceAll(part, "`", "\\`"))
+		writer.WriteByte('`')
+	}
+}
+
+var bindVarRegexp = regexp.MustCompile(`@p(\d+)`)
+
+func (d *Dialector) Explain(sql string, vars ...any) string {
+	return logger.ExplainSQL(sql, bindVarRegexp, `'`, vars...)
+}
+
+// Builds LIMIT and OFFSET clauses, as BigQuery only supports OFFSET after a
+// LIMIT.
+func buildLimit(c clause.Clause, builder clause.Builder) {
+	limit, ok := c.Expression.(clause.Limit)
+	if !ok {
+		c.Build(builder)
+		return
+	}
+	if limit.Limit == nil || *limit.Limit < 0 {
+		if limit.Offset <= 0 {
+			return
+		}
+		unlimited := math.MaxInt
+		limit.Limit = &unlimited
+	}
+	builder.WriteString("LIMIT ")
+	builder.AddVar(builder, *limit.Limit)
+	if limit.Offset > 0 {
+		builder.WriteString(" OFFSET ")
+		builder.AddVar(builder, limit.Offset)
+	}
+}
@@ -0,0 +1,138 @@
+package gorm
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	bq "google.golang.org/api/bigquery/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type user struct {
+	gorm.Model
+	Name string
+}
+
+type item struct {
+	ID    int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name  string
+	Count int64
+}
+
+func TestCreateWithoutLastInsertID(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{DMLAffected: 1}
+	})
+	db := server.open("dataset")
+
+	u := user{Name: "alice"}
+	result := db.Create(&u)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.RowsAffected != 1 {
+		t.Errorf("RowsAffected = %d, want 1", result.RowsAffected)
+	}
+
+	queries := server.takeQueries()
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	// The null deleted_at is written as NULL, as BigQuery parameters are typed.
+	want := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`) VALUES (@p1,@p2,NULL,@p4)"
+	if queries[0].SQL != want {
+		t.Errorf("SQL = %q, want %q", queries[0].SQL, want)
+	}
+	if _, ok := queries[0].Params["p3"]; ok || len(queries[0].Params) != 3 {
+		t.Errorf("params = %v, want p1, p2 and p4", queries[0].Params)
+	}
+	if queries[0].Params["p4"] != "alice" {
+		t.Errorf("p4 = %q, want %q", queries[0].Params["p4"], "alice")
+	}
+
+	// GORM runs creates in a transaction by default.
+	wantTx := []string{"BEGIN TRANSACTION;", "COMMIT TRANSACTION;"}
+	if tx := server.takeTxStatements(); !reflect.DeepEqual(tx, wantTx) {
+		t.Errorf("transaction statements = %q, want %q", tx, wantTx)
+	}
+}
+
+func TestNamedBinding(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{
+				{Name: "id", Type: "INTEGER"},
+				{Name: "name", Type: "STRING"},
+				{Name: "count", Type: "INTEGER"},
+			},
+			Rows: [][]any{{"1", "a", "2"}, {"3", "b", "4"}},
+		}
+	})
+	db := server.open("dataset")
+
+	var items []item
+	if err := db.
+		Where("name = @name", sql.Named("name", "a")).
+		Where("count > ?", 1).
+		Where("id IN (?)", db.Table("other").Select("id").Where("active = ?", true)).
+		Limit(10).Offset(5).
+		Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+	want := []item{{ID: 1, Name: "a", Count: 2}, {ID: 3, Name: "b", Count: 4}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+
+	queries := server.takeQueries()
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	wantSQL := "SELECT * FROM `items` WHERE name = @p1 AND count > @p2 AND id IN (SELECT id FROM `other` WHERE active = @p3) LIMIT @p4 OFFSET @p5"
+	if queries[0].SQL != wantSQL {
+		t.Errorf("SQL = %q, want %q", queries[0].SQL, wantSQL)
+	}
+	wantParams := map[string]string{"p1": "a", "p2": "1", "p3": "true", "p4": "10", "p5": "5"}
+	if !reflect.DeepEqual(queries[0].Params, wantParams) {
+		t.Errorf("params = %v, want %v", queries[0].Params, wantParams)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{DMLAffected: 2}
+	})
+	db := server.open("dataset")
+
+	items := []item{{ID: 1, Name: "a", Count: 2}, {ID: 3, Name: "b", Count: 4}}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "count"}),
+	}).Create(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	queries := server.takeQueries()
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	want := "MERGE INTO `items` AS target USING (" +
+		"SELECT @p1 AS `id`, @p2 AS `name`, @p3 AS `count` UNION ALL SELECT @p4, @p5, @p6) AS source " +
+		"ON `target`.`id` = `source`.`id` " +
+		"WHEN MATCHED THEN UPDATE SET `name` = `source`.`name`, `count` = `source`.`count` " +
+		"WHEN NOT MATCHED THEN INSERT (`id`, `name`, `count`) VALUES (`source`.`id`, `source`.`name`, `source`.`count`)"
+	if queries[0].SQL != want {
+		t.Errorf("SQL = %q, want %q", queries[0].SQL, want)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	d := &Dialector{}
+	got := d.Explain("SELECT * FROM `t` WHERE a = @p1 AND b = @p2 LIMIT @p10", "x", 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	want := "SELECT * FROM `t` WHERE a = 'x' AND b = 2 LIMIT 10"
+	if got != want {
+		t.Errorf("Explain = %q, want %q", got, want)
+	}
+}
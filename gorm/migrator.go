@@ -0,0 +1,370 @@
+package gorm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	_ gorm.Migrator = Migrator{}
+)
+
+// Migrator is a [gorm.Migrator] for BigQuery. Tables are looked up in the
+// INFORMATION_SCHEMA of the configured dataset (unless their names are
+// qualified with another dataset).
+//
+// Tables are created with an (unenforced) primary key, and partitioned and
+// clustered according to the `partition` and `cluster` tags of their fields:
+//
+//   - `gorm:"partition"` or `gorm:"partition:DAY"` (or HOUR, MONTH or YEAR)
+//     partitions by a DATE, DATETIME or TIMESTAMP column, truncated to the
+//     given granularity.
+//   - `gorm:"partition:0,1000,10"` partitions by an INT64 column, in ranges
+//     of the given start, end and interval.
+//   - `gorm:"cluster"` clusters by a column. Columns are clustered in the
+//     order of their fields, unless ordered explicitly with
+//     `gorm:"cluster:1"`, `gorm:"cluster:2"`, etc.
+//
+// BigQuery has neither indexes nor enforced constraints, so indexes and
+// (unique, foreign key and check) constraints are ignored.
+type Migrator struct {
+	migrator.Migrator
+	dialector *Dialector
+}
+
+func (m Migrator) CurrentDatabase() string {
+	return m.dialector.Config.Dataset
+}
+
+func (m Migrator) GetTables() (tableList []string, err error) {
+	dataset, _, err := m.tableName("")
+	if err != nil {
+		return nil, err
+	}
+	err = m.DB.Raw(
+		"SELECT table_name FROM " + m.informationSchema(dataset, "TABLES") + " WHERE table_type = 'BASE TABLE' ORDER BY table_name",
+	).Scan(&tableList).Error
+	return tableList, err
+}
+
+func (m Migrator) HasTable(value any) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		dataset, table, err := m.tableName(stmt.Table)
+		if err != nil {
+			return err
+		}
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM "+m.informationSchema(dataset, "TABLES")+" WHERE table_name = ?",
+			table,
+		).Scan(&count).Error
+	})
+	return count > 0
+}
+
+func (m Migrator) CreateTable(values ...any) error {
+	for _, value := range m.ReorderModels(values, false) {
+		tx := m.DB.Session(&gorm.Session{})
+		if err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+			if stmt.Schema == nil {
+				return errors.New("failed to get schema")
+			}
+
+			var columns []string
+			args := []any{m.CurrentTable(stmt)}
+			for _, dbName := range stmt.Schema.DBNames {
+				field := stmt.Schema.FieldsByDBName[dbName]
+				if field.IgnoreMigration {
+					continue
+				}
+				columns = append(columns, "? ?")
+				args = append(args, clause.Column{Name: dbName}, m.DB.Migrator().FullDataTypeOf(field))
+			}
+			if len(stmt.Schema.PrimaryFields) > 0 {
+				primaryKeys := make([]any, len(stmt.Schema.PrimaryFields))
+				for i, field := range stmt.Schema.PrimaryFields {
+					primaryKeys[i] = clause.Column{Name: field.DBName}
+				}
+				columns = append(columns, "PRIMARY KEY ? NOT ENFORCED")
+				args = append(args, primaryKeys)
+			}
+			createTableSQL := "CREATE TABLE ? (" + strings.Join(columns, ", ") + ")"
+
+			partition, err := m.partitionBy(stmt)
+			if err != nil {
+				return err
+			}
+			if partition != nil {
+				createTableSQL += " PARTITION BY ?"
+				args = append(args, *partition)
+			}
+			if cluster := clusterBy(stmt); len(cluster) > 0 {
+				createTableSQL += " CLUSTER BY " + strings.TrimSuffix(strings.Repeat("?, ", len(cluster)), ", ")
+				args = append(args, cluster...)
+			}
+			if tableOption, ok := m.DB.Get("gorm:table_options"); ok {
+				createTableSQL += " " + fmt.Sprint(tableOption)
+			}
+
+			return tx.Exec(createTableSQL, args...).Error
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the PARTITION BY expression of the table, if any of its fields is
+// tagged with `partition`.
+func (m Migrator) partitionBy(stmt *gorm.Statement) (*clause.Expr, error) {
+	var field *schema.Field
+	for _, f := range stmt.Schema.Fields {
+		if _, ok := f.TagSettings["PARTITION"]; ok && f.DBName != "" {
+			if field != nil {
+				return nil, fmt.Errorf("can't partition by more than one column: %s and %s", field.DBName, f.DBName)
+			}
+			field = f
+		}
+	}
+	if field == nil {
+		return nil, nil
+	}
+
+	setting := strings.ToUpper(strings.TrimSpace(field.TagSettings["PARTITION"]))
+	if setting == "PARTITION" {
+		setting = ""
+	}
+	column := clause.Column{Name: field.DBName}
+	invalid := fmt.Errorf("invalid partitioning of column %s: %q", field.DBName, field.TagSettings["PARTITION"])
+
+	switch dataType := strings.ToUpper(m.DataTypeOf(field)); dataType {
+	case "DATE":
+		switch setting {
+		case "", "DAY":
+			return &clause.Expr{SQL: "?", Vars: []any{column}}, nil
+		case "MONTH", "YEAR":
+			return &clause.Expr{SQL: "DATE_TRUNC(?, " + setting + ")", Vars: []any{column}}, nil
+		}
+	case "DATETIME", "TIMESTAMP":
+		switch setting {
+		case "":
+			setting = "DAY"
+			fallthrough
+		case "DAY", "HOUR", "MONTH", "YEAR":
+			return &clause.Expr{SQL: dataType + "_TRUNC(?, " + setting + ")", Vars: []any{column}}, nil
+		}
+	case "INT64":
+		bounds := strings.Split(setting, ",")
+		if len(bounds) != 3 {
+			return nil, invalid
+		}
+		for i, bound := range bounds {
+			bounds[i] = strings.TrimSpace(bound)
+			if _, err := strconv.ParseInt(bounds[i], 10, 64); err != nil {
+				return nil, invalid
+			}
+		}
+		return &clause.Expr{
+			SQL:  "RANGE_BUCKET(?, GENERATE_ARRAY(" + strings.Join(bounds, ", ") + "))",
+			Vars: []any{column},
+		}, nil
+	}
+	return nil, invalid
+}
+
+// Returns the CLUSTER BY columns of the table, i.e. those of fields tagged with
+// `cluster`, in the order given by the tags (or else the order of the fields).
+func clusterBy(stmt *gorm.Statement) []any {
+	type clusterField struct {
+		order  int
+		column clause.Column
+	}
+	var fields []clusterField
+	for _, f := range stmt.Schema.Fields {
+		if setting, ok := f.TagSettings["CLUSTER"]; ok && f.DBName != "" {
+			order, _ := strconv.Atoi(setting)
+			fields = append(fields, clusterField{order: order, column: clause.Column{Name: f.DBName}})
+		}
+	}
+	slices.SortStableFunc(fields, func(a, b clusterField) int {
+		return a.order - b.order
+	})
+
+	columns := make([]any, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+	return columns
+}
+
+func (m Migrator) AddColumn(value any, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		field, err := lookUpField(stmt, name)
+		if err != nil || field.IgnoreMigration {
+			return err
+		}
+		return m.DB.Exec(
+			"ALTER TABLE ? ADD COLUMN ? ?",
+			m.CurrentTable(stmt), clause.Column{Name: field.DBName}, m.DB.Migrator().FullDataTypeOf(field),
+		).Error
+	})
+}
+
+// AlterColumn changes the data type of a column, which BigQuery only allows
+// for some conversions (e.g. from INT64 to NUMERIC).
+func (m Migrator) AlterColumn(value any, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		field, err := lookUpField(stmt, name)
+		if err != nil || field.IgnoreMigration {
+			return err
+		}
+		return m.DB.Exec(
+			"ALTER TABLE ? ALTER COLUMN ? SET DATA TYPE ?",
+			m.CurrentTable(stmt), clause.Column{Name: field.DBName}, clause.Expr{SQL: m.DataTypeOf(field)},
+		).Error
+	})
+}
+
+// MigrateColumn alters the column if its data type differs from the field's.
+func (m Migrator) MigrateColumn(value any, field *schema.Field, columnType gorm.ColumnType) error {
+	if field.IgnoreMigration {
+		return nil
+	}
+	if normalizeType(m.DataTypeOf(field)) != normalizeType(columnType.DatabaseTypeName()) {
+		return m.DB.Migrator().AlterColumn(value, field.DBName)
+	}
+	return nil
+}
+
+func (m Migrator) HasColumn(value any, name string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if stmt.Schema != nil {
+			if field := stmt.Schema.LookUpField(name); field != nil {
+				name = field.DBName
+			}
+		}
+		dataset, table, err := m.tableName(stmt.Table)
+		if err != nil {
+			return err
+		}
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM "+m.informationSchema(dataset, "COLUMNS")+" WHERE table_name = ? AND column_name = ?",
+			table, name,
+		).Scan(&count).Error
+	})
+	return count > 0
+}
+
+func (m Migrator) ColumnTypes(value any) ([]gorm.ColumnType, error) {
+	var columnTypes []gorm.ColumnType
+	err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		dataset, table, err := m.tableName(stmt.Table)
+		if err != nil {
+			return err
+		}
+		rows, err := m.DB.Raw(
+			"SELECT column_name, data_type, is_nullable, column_default FROM "+m.informationSchema(dataset, "COLUMNS")+" WHERE table_name = ? ORDER BY ordinal_position",
+			table,
+		).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name, dataType, nullable, defaultValue string
+			if err := rows.Scan(&name, &dataType, &nullable, &defaultValue); err != nil {
+				return err
+			}
+			columnTypes = append(columnTypes, migrator.ColumnType{
+				NameValue:         sql.NullString{String: name, Valid: true},
+				DataTypeValue:     sql.NullString{String: dataType, Valid: true},
+				ColumnTypeValue:   sql.NullString{String: dataType, Valid: true},
+				NullableValue:     sql.NullBool{Bool: nullable == "YES", Valid: true},
+				DefaultValueValue: sql.NullString{String: defaultValue, Valid: defaultValue != "NULL"},
+			})
+		}
+		return rows.Err()
+	})
+	return columnTypes, err
+}
+
+func (m Migrator) CreateIndex(value any, name string) error {
+	return nil
+}
+
+func (m Migrator) DropIndex(value any, name string) error {
+	return nil
+}
+
+func (m Migrator) HasIndex(value any, name string) bool {
+	return false
+}
+
+func (m Migrator) RenameIndex(value any, oldName, newName string) error {
+	return nil
+}
+
+func (m Migrator) GetIndexes(value any) ([]gorm.Index, error) {
+	return nil, nil
+}
+
+func (m Migrator) CreateConstraint(value any, name string) error {
+	return nil
+}
+
+func (m Migrator) DropConstraint(value any, name string) error {
+	return nil
+}
+
+func (m Migrator) HasConstraint(value any, name string) bool {
+	return false
+}
+
+// Splits a (possibly qualified) table name into its dataset (which defaults to
+// the configured dataset) and its unqualified name.
+func (m Migrator) tableName(name string) (string, string, error) {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i], name[i+1:], nil
+	}
+	dataset := m.dialector.Config.Dataset
+	if dataset == "" {
+		return "", "", errors.New("no dataset configured (set Config.Dataset, or qualify table names with a dataset)")
+	}
+	if project := m.dialector.Config.DatasetProject; project != "" {
+		dataset = project + "." + dataset
+	}
+	return dataset, name, nil
+}
+
+// Returns a reference to an INFORMATION_SCHEMA view of a dataset.
+func (m Migrator) informationSchema(dataset, view string) string {
+	return m.DB.Statement.Quote(dataset) + ".INFORMATION_SCHEMA." + view
+}
+
+func lookUpField(stmt *gorm.Statement, name string) (*schema.Field, error) {
+	if stmt.Schema == nil {
+		return nil, errors.New("failed to get schema")
+	}
+	field := stmt.Schema.LookUpField(name)
+	if field == nil {
+		return nil, fmt.Errorf("failed to look up field with name: %s", name)
+	}
+	return field, nil
+}
+
+// Normalizes a data type for comparison, e.g. "STRUCT<`a` INT64>" and
+// "STRUCT<a INT64>" are the same type.
+func normalizeType(dataType string) string {
+	return strings.ToUpper(strings.NewReplacer("`", "", " ", "").Replace(dataType))
+}
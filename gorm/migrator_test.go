@@ -0,0 +1,86 @@
+package gorm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+type event struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement:false"`
+	Kind      string    `gorm:"cluster:2"`
+	Source    string    `gorm:"cluster:1"`
+	CreatedAt time.Time `gorm:"partition:DAY"`
+}
+
+func TestCreateTable(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{}
+	})
+	db := server.open("dataset")
+
+	if err := db.Migrator().CreateTable(&event{}); err != nil {
+		t.Fatal(err)
+	}
+	queries := server.takeQueries()
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	want := "CREATE TABLE `events` (`id` INT64, `kind` STRING, `source` STRING, `created_at` TIMESTAMP, " +
+		"PRIMARY KEY (`id`) NOT ENFORCED) " +
+		"PARTITION BY TIMESTAMP_TRUNC(`created_at`, DAY) CLUSTER BY `source`, `kind`"
+	if queries[0].SQL != want {
+		t.Errorf("SQL = %q, want %q", queries[0].SQL, want)
+	}
+}
+
+func TestHasTable(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "f0_", Type: "INTEGER"}},
+			Rows:   [][]any{{"1"}},
+		}
+	})
+	db := server.open("dataset")
+
+	if !db.Migrator().HasTable(&event{}) {
+		t.Error("HasTable = false, want true")
+	}
+	if !db.Migrator().HasTable("other.events") {
+		t.Error("HasTable = false, want true")
+	}
+
+	queries := server.takeQueries()
+	want := []fakeQuery{
+		{
+			SQL:    "SELECT COUNT(*) FROM `dataset`.INFORMATION_SCHEMA.TABLES WHERE table_name = @p1",
+			Params: map[string]string{"p1": "events"},
+		},
+		{
+			SQL:    "SELECT COUNT(*) FROM `other`.INFORMATION_SCHEMA.TABLES WHERE table_name = @p1",
+			Params: map[string]string{"p1": "events"},
+		},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %+v, want %+v", queries, want)
+	}
+}
+
+func TestMigratorWithoutDataset(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{}
+	})
+	db := server.open("")
+
+	if _, err := db.Migrator().GetTables(); err == nil {
+		t.Error("GetTables succeeded without a dataset")
+	}
+	if db.Migrator().HasTable(&event{}) {
+		t.Error("HasTable = true without a dataset")
+	}
+	if queries := server.takeQueries(); len(queries) != 0 {
+		t.Errorf("got %d queries, want none", len(queries))
+	}
+}
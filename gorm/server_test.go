@@ -0,0 +1,188 @@
+package gorm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	bigquery "github.com/timescale/bigquery-go-client"
+	bq "google.golang.org/api/bigquery/v2"
+	"gorm.io/gorm"
+)
+
+// A query received by the fake server.
+type fakeQuery struct {
+	SQL    string
+	Params map[string]string
+}
+
+// The result of a query run by the fake server.
+type fakeResult struct {
+	Schema      []*bq.TableFieldSchema
+	Rows        [][]any
+	DMLAffected int64
+}
+
+// A fake BigQuery API server, which records the queries it receives, and
+// returns the results of the handler for them.
+type fakeServer struct {
+	*httptest.Server
+	t       *testing.T
+	handler func(q fakeQuery) fakeResult
+
+	mu           sync.Mutex
+	queries      []fakeQuery
+	txStatements []string
+	jobs         map[string]*bq.Job
+	results      map[string]fakeResult
+}
+
+var (
+	insertJobPath = regexp.MustCompile(`/projects/[^/]+/jobs$`)
+	getJobPath    = regexp.MustCompile(`/projects/[^/]+/jobs/([^/]+)$`)
+	queryPath     = regexp.MustCompile(`/projects/[^/]+/queries/([^/]+)$`)
+)
+
+func newFakeServer(t *testing.T, handler func(q fakeQuery) fakeResult) *fakeServer {
+	s := &fakeServer{
+		t:       t,
+		handler: handler,
+		jobs:    map[string]*bq.Job{},
+		results: map[string]fakeResult{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Opens a GORM database connected to the fake server, with the given dataset
+// as the default dataset.
+func (s *fakeServer) open(dataset string) *gorm.DB {
+	s.t.Helper()
+	db, err := gorm.Open(New(bigquery.Config{
+		ProjectID:   "project",
+		Dataset:     dataset,
+		Endpoint:    s.URL + "/",
+		DisableAuth: true,
+	}), &gorm.Config{})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return db
+}
+
+// Returns the queries received so far (other than transaction control
+// statements), and forgets them.
+func (s *fakeServer) takeQueries() []fakeQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queries := s.queries
+	s.queries = nil
+	return queries
+}
+
+// Returns the transaction control statements received so far, and forgets
+// them.
+func (s *fakeServer) takeTxStatements() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statements := s.txStatements
+	s.txStatements = nil
+	return statements
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var response any
+	switch {
+	case r.Method == http.MethodPost && insertJobPath.MatchString(r.URL.Path):
+		var job bq.Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response = s.insertJob(&job)
+	case r.Method == http.MethodGet && getJobPath.MatchString(r.URL.Path):
+		job, ok := s.jobs[getJobPath.FindStringSubmatch(r.URL.Path)[1]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		response = job
+	case r.Method == http.MethodGet && queryPath.MatchString(r.URL.Path):
+		jobID := queryPath.FindStringSubmatch(r.URL.Path)[1]
+		job, ok := s.jobs[jobID]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		response = queryResults(job, s.results[jobID], r.URL.Query().Get("maxResults") == "0")
+	default:
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *fakeServer) insertJob(job *bq.Job) *bq.Job {
+	query := fakeQuery{
+		SQL:    job.Configuration.Query.Query,
+		Params: map[string]string{},
+	}
+	for _, param := range job.Configuration.Query.QueryParameters {
+		query.Params[param.Name] = param.ParameterValue.Value
+	}
+	if strings.HasSuffix(query.SQL, " TRANSACTION;") {
+		s.txStatements = append(s.txStatements, query.SQL)
+	} else {
+		s.queries = append(s.queries, query)
+	}
+
+	result := s.handler(query)
+	statementType, _, _ := strings.Cut(strings.TrimSpace(query.SQL), " ")
+	job.Status = &bq.JobStatus{State: "DONE"}
+	job.Statistics = &bq.JobStatistics{
+		Query: &bq.JobStatistics2{
+			StatementType:      strings.ToUpper(statementType),
+			NumDmlAffectedRows: result.DMLAffected,
+		},
+	}
+	s.jobs[job.JobReference.JobId] = job
+	s.results[job.JobReference.JobId] = result
+	return job
+}
+
+func queryResults(job *bq.Job, result fakeResult, schemaOnly bool) *bq.GetQueryResultsResponse {
+	response := &bq.GetQueryResultsResponse{
+		JobReference:       job.JobReference,
+		JobComplete:        true,
+		TotalRows:          uint64(len(result.Rows)),
+		NumDmlAffectedRows: result.DMLAffected,
+	}
+	// Like BigQuery, report the affected rows of DML statements as the total.
+	if result.DMLAffected > 0 {
+		response.TotalRows = uint64(result.DMLAffected)
+	}
+	if result.Schema != nil {
+		response.Schema = &bq.TableSchema{Fields: result.Schema}
+	}
+	if !schemaOnly {
+		for _, row := range result.Rows {
+			cells := make([]*bq.TableCell, len(row))
+			for i, value := range row {
+				cells[i] = &bq.TableCell{V: value}
+			}
+			response.Rows = append(response.Rows, &bq.TableRow{F: cells})
+		}
+	}
+	return response
+}
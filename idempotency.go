@@ -0,0 +1,131 @@
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+type jobIDKey struct{}
+type idempotencyKey struct{}
+type sessionRetryKey struct{}
+
+// WithJobID returns a copy of the context with the given job ID attached,
+// which is used as the (exact) ID of the job for a statement executed with the
+// returned context. If a job with the ID already exists (e.g. when retrying a
+// statement that failed with a network error after its job was created), the
+// statement attaches to the existing job and returns its results (or its
+// error, if it failed) instead of being run again. Since job IDs are unique,
+// the context should only be used for a single statement. If the statement is
+// re-run in a new session since its session expired, the job of the re-run
+// has the ID with a "_retry" suffix.
+func WithJobID(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobIDKey{}, jobID)
+}
+
+func jobIDFromContext(ctx context.Context) string {
+	jobID, _ := ctx.Value(jobIDKey{}).(string)
+	return jobID
+}
+
+// WithIdempotencyKey returns a copy of the context with the given idempotency
+// key attached. Job IDs for statements executed with the returned context are
+// derived from the key, the statement's text and its parameters (and prefixed
+// with the prefix attached via [WithJobIDPrefix], if any), so that retrying a
+// statement with the same key and parameters attaches to the existing job, as
+// described for [WithJobID].
+// Note that a failed job is attached to as well, so a new key is needed to
+// re-run a failed statement.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+// Returns a copy of the context for re-running a statement in a new session,
+// whose job needs an ID other than that of the failed job.
+func withSessionRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionRetryKey{}, true)
+}
+
+// Returns the deterministic job ID for the statement, if any.
+func deterministicJobID(ctx context.Context, query string, params []bigquery.QueryParameter) (string, error) {
+	jobID, key := jobIDFromContext(ctx), idempotencyKeyFromContext(ctx)
+	if jobID == "" && key == "" {
+		return "", nil
+	}
+	if jobID != "" && key != "" {
+		return "", errors.New("a job ID and an idempotency key cannot both be specified")
+	}
+	if key != "" {
+		hash, err := hashStatement(key, query, params)
+		if err != nil {
+			return "", err
+		}
+		jobID = jobIDPrefixFromContext(ctx) + hash
+	} else if jobID != "" && jobIDPrefixFromContext(ctx) != "" {
+		return "", errors.New("a job ID and a job ID prefix cannot both be specified")
+	}
+	if retry, _ := ctx.Value(sessionRetryKey{}).(bool); retry {
+		jobID += "_retry"
+	}
+	if len(jobID) > maxJobIDLength || !jobIDRegexp.MatchString(jobID) {
+		return "", fmt.Errorf("invalid job ID: %q", jobID)
+	}
+	return jobID, nil
+}
+
+// Returns a hash of the idempotency key, the query and the name, type and
+// value of each of its parameters.
+func hashStatement(key, query string, params []bigquery.QueryParameter) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q", key, query)
+	for _, param := range params {
+		value, err := json.Marshal(param.Value)
+		if err != nil {
+			return "", fmt.Errorf("cannot derive job ID from parameter %q: %w", param.Name, err)
+		}
+		fmt.Fprintf(h, " %q %T %s", param.Name, param.Value, value)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Reports whether the error indicates that a job with the same ID already
+// exists.
+func duplicateJobError(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
+
+// Attaches to the existing job with the query's job ID, and waits for it to
+// complete.
+func (c *conn) attachJob(ctx context.Context, query *bigquery.Query) (*bigquery.Job, error) {
+	projectID := query.ProjectID
+	if projectID == "" {
+		projectID = c.client.Project()
+	}
+	location := query.Location
+	if location == "" {
+		location = c.client.Location
+	}
+
+	job, err := c.client.JobFromProject(ctx, projectID, query.JobID, location)
+	if err != nil {
+		return nil, err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return job, err
+	}
+	return job, status.Err()
+}
@@ -0,0 +1,47 @@
+package bigquery
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// QueryInvoker submits a query and waits for the resulting job to complete.
+// The job is returned even if it failed, whenever it was created.
+type QueryInvoker func(ctx context.Context, query *bigquery.Query) (*bigquery.Job, error)
+
+// Interceptor is a function that wraps the execution of every query run by
+// connections created from a connector (see [Config.Interceptors]). An
+// interceptor can inspect or modify the query before calling invoker to
+// submit it, and inspect the job and error once it has completed (e.g. for
+// auditing). It can also reject a query by returning an error without calling
+// invoker at all.
+type Interceptor func(ctx context.Context, query *bigquery.Query, invoker QueryInvoker) (*bigquery.Job, error)
+
+func chainInterceptors(interceptors []Interceptor, invoker QueryInvoker) QueryInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, query *bigquery.Query) (*bigquery.Job, error) {
+			return interceptor(ctx, query, next)
+		}
+	}
+	return invoker
+}
+
+func runQuery(ctx context.Context, query *bigquery.Query) (*bigquery.Job, error) {
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dry runs don't create a job that can be waited on.
+	if query.DryRun {
+		return job, nil
+	}
+
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return job, err
+	}
+	return job, status.Err()
+}
@@ -0,0 +1,119 @@
+package bigquery
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"cloud.google.com/go/bigquery"
+)
+
+var (
+	_ sql.Scanner   = (*JSON[any])(nil)
+	_ driver.Valuer = JSON[any]{}
+)
+
+// JSON is a nullable JSON value, which can be used to scan JSON columns
+// (decoding them into a value of type T), and to pass JSON query parameters
+// (encoding V as JSON).
+type JSON[T any] struct {
+	V     T
+	Valid bool
+
+	// UseNumber causes numbers to be decoded as [json.Number] values (rather
+	// than float64 values) when scanning into interface values, which
+	// preserves the precision of large numbers. It must be set before calling
+	// Scan.
+	UseNumber bool
+}
+
+func (j *JSON[T]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		j.V, j.Valid = *new(T), false
+		return nil
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, j)
+	}
+
+	var v T
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if j.UseNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(&v); err != nil {
+		return fmt.Errorf("error decoding JSON into %T: %w", v, err)
+	}
+
+	j.V, j.Valid = v, true
+	return nil
+}
+
+// Value returns the JSON encoding of V as a string.
+func (j JSON[T]) Value() (driver.Value, error) {
+	if !j.Valid {
+		return nil, nil
+	}
+	out, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+	return string(out), nil
+}
+
+func (j JSON[T]) jsonParam() (bigquery.NullJSON, error) {
+	if !j.Valid {
+		return bigquery.NullJSON{}, nil
+	}
+	out, err := json.Marshal(j.V)
+	if err != nil {
+		return bigquery.NullJSON{}, err
+	}
+	return bigquery.NullJSON{JSONVal: string(out), Valid: true}, nil
+}
+
+type jsonParam interface {
+	jsonParam() (bigquery.NullJSON, error)
+}
+
+// Converts JSON and json.RawMessage values to JSON query parameters (by
+// default, they'd be passed as STRING or BYTES parameters).
+func checkJSONValue(named *driver.NamedValue) (bool, error) {
+	switch value := named.Value.(type) {
+	case jsonParam:
+		// Nil *JSON values are handled by the default converter (as NULL).
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			return false, nil
+		}
+		param, err := value.jsonParam()
+		if err != nil {
+			return true, err
+		}
+		named.Value = param
+		return true, nil
+	case json.RawMessage:
+		named.Value = bigquery.NullJSON{JSONVal: string(value), Valid: value != nil}
+		return true, nil
+	case bigquery.NullJSON:
+		return true, nil
+	}
+	return false, nil
+}
+
+var rawMessagePtrType = reflect.PointerTo(reflect.TypeFor[json.RawMessage]())
+
+func convertJSONType(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
+	val, err := convertBytesType[string](field, value)
+	if err != nil || val == nil || !conv.jsonRawMessage {
+		return val, err
+	}
+	return json.RawMessage(val.([]byte)), nil
+}
@@ -0,0 +1,232 @@
+package bigquery
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Limits the number of jobs run concurrently by the connections of a
+// connector, with a separate FIFO queue per priority.
+type jobLimiter struct {
+	interactive *jobQueue
+	batch       *jobQueue
+	// The number of jobs each batch job counts as, if batch jobs share the
+	// interactive queue.
+	batchWeight int
+}
+
+func newJobLimiter(config Config) *jobLimiter {
+	if config.MaxConcurrentJobs <= 0 && config.MaxConcurrentBatchJobs <= 0 {
+		return nil
+	}
+
+	l := &jobLimiter{batchWeight: 1}
+	if config.MaxConcurrentJobs > 0 {
+		l.interactive = &jobQueue{limit: config.MaxConcurrentJobs}
+	}
+	// Batch jobs share the interactive limit, unless they have their own.
+	l.batch = l.interactive
+	if config.MaxConcurrentBatchJobs > 0 {
+		l.batch = &jobQueue{limit: config.MaxConcurrentBatchJobs}
+	} else if config.BatchJobWeight > 1 {
+		l.batchWeight = config.BatchJobWeight
+	}
+	return l
+}
+
+// Returns the queue for jobs of the given priority, and the number of jobs
+// each job counts as in it.
+func (l *jobLimiter) queue(priority bigquery.QueryPriority) (*jobQueue, int) {
+	if l == nil {
+		return nil, 0
+	}
+	if priority == bigquery.BatchPriority {
+		return l.batch, l.batchWeight
+	}
+	return l.interactive, 1
+}
+
+// Wraps the invoker, so that it waits for the job to be admitted by the
+// queue for the query's priority (or for the context to be done) before
+// running it.
+func (l *jobLimiter) wrap(invoker QueryInvoker) QueryInvoker {
+	if l == nil {
+		return invoker
+	}
+	return func(ctx context.Context, query *bigquery.Query) (*bigquery.Job, error) {
+		// Dry runs don't count towards the concurrent query limits.
+		q, weight := l.queue(query.Priority)
+		if q == nil || query.DryRun {
+			return invoker(ctx, query)
+		}
+		// A job can't count as more jobs than the limit, or it would never
+		// run.
+		weight = min(weight, q.limit)
+		if err := q.acquire(ctx, weight); err != nil {
+			return nil, err
+		}
+		defer q.release(weight)
+		return invoker(ctx, query)
+	}
+}
+
+type jobQueue struct {
+	limit int
+
+	mu      sync.Mutex
+	running int
+	// The weighted number of running jobs, which is limited.
+	used    int
+	waiters list.List // of *jobWaiter
+
+	// Metrics.
+	waits     int64
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+// A job waiting in a queue, which is admitted by closing ready.
+type jobWaiter struct {
+	ready  chan struct{}
+	weight int
+}
+
+func (q *jobQueue) acquire(ctx context.Context, weight int) error {
+	q.mu.Lock()
+	if q.used+weight <= q.limit && q.waiters.Len() == 0 {
+		q.running++
+		q.used += weight
+		q.mu.Unlock()
+		return nil
+	}
+	waiter := &jobWaiter{ready: make(chan struct{}), weight: weight}
+	elem := q.waiters.PushBack(waiter)
+	q.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-waiter.ready:
+		q.recordWait(time.Since(start))
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-waiter.ready:
+			// The job was admitted concurrently, so pass the slot on.
+			q.mu.Unlock()
+			q.release(weight)
+		default:
+			q.waiters.Remove(elem)
+			// Jobs behind the removed one may fit now.
+			q.admit()
+			q.mu.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// Releases the slots of a job, handing them over to the waiters at the front
+// of the queue (if any).
+func (q *jobQueue) release(weight int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	q.used -= weight
+	q.admit()
+}
+
+// Admits waiters from the front of the queue while they fit in the limit.
+// Must be called with q.mu held.
+func (q *jobQueue) admit() {
+	for front := q.waiters.Front(); front != nil; front = q.waiters.Front() {
+		waiter := front.Value.(*jobWaiter)
+		if q.used+waiter.weight > q.limit {
+			return
+		}
+		q.waiters.Remove(front)
+		q.running++
+		q.used += waiter.weight
+		close(waiter.ready)
+	}
+}
+
+func (q *jobQueue) recordWait(wait time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.waits++
+	q.totalWait += wait
+	q.maxWait = max(q.maxWait, wait)
+}
+
+func (q *jobQueue) stats() PriorityQueueStats {
+	if q == nil {
+		return PriorityQueueStats{}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return PriorityQueueStats{
+		Limit:     q.limit,
+		Running:   q.running,
+		Used:      q.used,
+		Queued:    q.waiters.Len(),
+		Waits:     q.waits,
+		TotalWait: q.totalWait,
+		MaxWait:   q.maxWait,
+	}
+}
+
+// JobQueueStats reports the state of the queues that limit the number of
+// concurrently running jobs (see [Config.MaxConcurrentJobs]).
+type JobQueueStats struct {
+	Interactive PriorityQueueStats
+	// Batch is the same as Interactive if batch jobs share its limit.
+	Batch PriorityQueueStats
+}
+
+// PriorityQueueStats reports the state of the queue for a job priority.
+type PriorityQueueStats struct {
+	// Limit is the maximum number of concurrently running jobs (0 if
+	// unlimited).
+	Limit int
+	// Running and Queued are the numbers of jobs currently running and
+	// waiting to run.
+	Running int
+	Queued  int
+	// Used is the number of jobs the running jobs count as towards the
+	// limit, which differs from Running if batch jobs are weighted (see
+	// [Config.BatchJobWeight]).
+	Used int
+	// Waits is the number of jobs that had to wait before running, and
+	// TotalWait and MaxWait are the total and maximum time they waited.
+	Waits     int64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// QueueStats returns the state of the job queues of a database opened with
+// this driver (via [sql.Open] or [sql.OpenDB]). Unlike a query, it doesn't
+// need a connection from the pool, so it can be used to monitor the queues
+// while they're full.
+func QueueStats(db *sql.DB) (JobQueueStats, error) {
+	d, ok := db.Driver().(*bigQueryDriver)
+	if !ok || d.connector == nil {
+		return JobQueueStats{}, errors.New("not a BigQuery database")
+	}
+	return d.connector.queueStats(), nil
+}
+
+func (c *connector) queueStats() JobQueueStats {
+	if c.limiter == nil {
+		return JobQueueStats{}
+	}
+	return JobQueueStats{
+		Interactive: c.limiter.interactive.stats(),
+		Batch:       c.limiter.batch.stats(),
+	}
+}
@@ -0,0 +1,149 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// Reports whether the channel is closed within a short time.
+func closedSoon(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+func TestJobQueueWeights(t *testing.T) {
+	q := &jobQueue{limit: 3}
+	ctx := context.Background()
+
+	if err := q.acquire(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	// Returns a channel that's closed once a job of the given weight is
+	// admitted.
+	acquired := func(weight int) <-chan struct{} {
+		ch := make(chan struct{})
+		go func() {
+			if err := q.acquire(ctx, weight); err == nil {
+				close(ch)
+			}
+		}()
+		return ch
+	}
+
+	// A job of weight 2 doesn't fit, and jobs behind it wait their turn.
+	heavy := acquired(2)
+	if closedSoon(heavy) {
+		t.Fatal("job admitted beyond the limit")
+	}
+	light := acquired(1)
+	if closedSoon(light) {
+		t.Fatal("job admitted ahead of a queued job")
+	}
+	if stats := q.stats(); stats.Running != 1 || stats.Used != 2 || stats.Queued != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Releasing the first job admits both queued jobs.
+	q.release(2)
+	if !closedSoon(heavy) || !closedSoon(light) {
+		t.Fatal("queued jobs not admitted")
+	}
+	if stats := q.stats(); stats.Running != 2 || stats.Used != 3 || stats.Queued != 0 || stats.Waits != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// A cancelled waiter at the front of the queue lets the jobs behind it
+	// through.
+	q.release(2)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancelled := make(chan error, 1)
+	go func() { cancelled <- q.acquire(cancelCtx, 3) }()
+	time.Sleep(10 * time.Millisecond)
+	next := acquired(1)
+	if closedSoon(next) {
+		t.Fatal("job admitted ahead of a queued job")
+	}
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire with a cancelled context: %v", err)
+	}
+	if !closedSoon(next) {
+		t.Fatal("job not admitted after the job ahead of it was cancelled")
+	}
+}
+
+func TestQueueStats(t *testing.T) {
+	db, err := sql.Open("bigquery", "bigquery://project/dataset?maxConcurrentJobs=4&batchJobWeight=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	stats, err := QueueStats(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Interactive.Limit != 4 || stats.Batch.Limit != 4 {
+		t.Errorf("QueueStats = %+v", stats)
+	}
+
+	d := db.Driver().(*bigQueryDriver)
+	q, weight := d.connector.limiter.queue(bigquery.BatchPriority)
+	if q != d.connector.limiter.interactive || weight != 2 {
+		t.Errorf("batch queue = %p with weight %d, want the interactive queue with weight 2", q, weight)
+	}
+
+	db = sql.OpenDB(NewConnector(Config{ProjectID: "project", MaxConcurrentJobs: 1, MaxConcurrentBatchJobs: 2, BatchJobWeight: 2}))
+	defer db.Close()
+	if stats, err := QueueStats(db); err != nil || stats.Batch.Limit != 2 {
+		t.Errorf("QueueStats = %+v, %v", stats, err)
+	}
+	d = db.Driver().(*bigQueryDriver)
+	if _, weight := d.connector.limiter.queue(bigquery.BatchPriority); weight != 1 {
+		t.Errorf("batch jobs with their own limit have weight %d, want 1", weight)
+	}
+}
+
+func TestFullPingLimited(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "f0_", Type: "INTEGER"}},
+			Rows:   [][]any{{"1"}},
+		}
+	})
+	config := server.config()
+	config.Dataset = ""
+	config.MaxConcurrentJobs = 1
+	config.PingMode = PingModeFull
+	db := server.open(config)
+
+	queue := db.Driver().(*bigQueryDriver).connector.limiter.interactive
+	if err := queue.acquire(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := db.PingContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ping while the limit is reached: got %v, want a deadline error", err)
+	}
+	if queries := server.takeQueries(); len(queries) != 0 {
+		t.Errorf("Ping ran %d queries while the limit was reached", len(queries))
+	}
+
+	queue.release(1)
+	if err := db.PingContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if queries := server.takeQueries(); len(queries) != 1 {
+		t.Errorf("Ping ran %d queries, want 1", len(queries))
+	}
+}
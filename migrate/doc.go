@@ -0,0 +1,12 @@
+// Package migrate implements schema migration drivers for BigQuery, on top of
+// the database/sql driver of the parent package: a golang-migrate database
+// driver ([Migrate]), and a goose dialect ([GooseQuerier]) and session locker
+// ([GooseLocker]).
+//
+// BigQuery has no advisory locks, so both emulate them with a lock table in
+// the default dataset, whose single row is claimed and released with
+// conditional UPDATE statements. A lock left behind by a crashed process can
+// be released manually with:
+//
+//	UPDATE schema_migrations_lock SET owner = NULL, acquired_at = NULL WHERE id = 1;
+package migrate
@@ -0,0 +1,260 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/database"
+)
+
+func init() {
+	database.Register("bigquery", &Migrate{})
+}
+
+var (
+	_ database.Driver = (*Migrate)(nil)
+)
+
+// DefaultMigrationsTable is the default name of the golang-migrate version
+// table.
+const DefaultMigrationsTable = "schema_migrations"
+
+// Config configures the golang-migrate driver. Table names are resolved
+// relative to the default dataset of the connection, unless qualified with a
+// dataset.
+type Config struct {
+	// MigrationsTable is the name of the version table (DefaultMigrationsTable
+	// by default).
+	MigrationsTable string
+
+	// LockTable is the name of the lock table (the name of the version table
+	// with a _lock suffix by default).
+	LockTable string
+}
+
+// Migrate is a golang-migrate [database.Driver] for BigQuery, which is
+// registered for "bigquery://" URLs (i.e. DSNs of the parent package, with the
+// optional x-migrations-table and x-lock-table parameters).
+//
+// All statements are run on a single connection (and therefore in a single
+// BigQuery session), and migrations are run as scripts, in a transaction if
+// BigQuery allows all of their statements in one (see [Migrate.Run]).
+type Migrate struct {
+	// The database opened by Open, if any, which is closed with the driver.
+	db     *sql.DB
+	conn   *sql.Conn
+	config Config
+	lock   *tableLock
+}
+
+// WithInstance returns a driver which runs migrations on a connection of db,
+// creating the version and lock tables if they don't exist.
+func WithInstance(db *sql.DB, config *Config) (database.Driver, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	m := &Migrate{config: *config}
+	if m.config.MigrationsTable == "" {
+		m.config.MigrationsTable = DefaultMigrationsTable
+	}
+	if m.config.LockTable == "" {
+		m.config.LockTable = m.config.MigrationsTable + "_lock"
+	}
+	m.lock = newTableLock(m.config.LockTable)
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.conn = conn
+
+	if _, err := conn.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS "+quoteName(m.config.MigrationsTable)+" (version INT64 NOT NULL, dirty BOOL NOT NULL);",
+	); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create version table %s: %w", m.config.MigrationsTable, err)
+	}
+	if err := m.lock.create(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Migrate) Open(dsn string) (database.Driver, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	config := &Config{
+		MigrationsTable: query.Get("x-migrations-table"),
+		LockTable:       query.Get("x-lock-table"),
+	}
+	for key := range query {
+		if strings.HasPrefix(key, "x-") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	db, err := sql.Open("bigquery", u.String())
+	if err != nil {
+		return nil, err
+	}
+	driver, err := WithInstance(db, config)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	driver.(*Migrate).db = db
+	return driver, nil
+}
+
+func (m *Migrate) Close() error {
+	err := m.conn.Close()
+	if m.db != nil {
+		err = errors.Join(err, m.db.Close())
+	}
+	return err
+}
+
+// Lock acquires the lock by updating the row of the lock table, or returns
+// [database.ErrLocked] if it's held by another driver instance.
+func (m *Migrate) Lock() error {
+	locked, err := m.lock.tryLock(context.Background(), m.conn)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return database.ErrLocked
+	}
+	return nil
+}
+
+func (m *Migrate) Unlock() error {
+	return m.lock.unlock(context.Background(), m.conn)
+}
+
+// Run runs the migration as a single (possibly multi-statement) script. If
+// all of its statements are DML (or SELECT, DECLARE and SET statements), it's
+// run in a transaction. BigQuery doesn't allow DDL statements in
+// transactions, so migrations containing them aren't atomic.
+func (m *Migrate) Run(migration io.Reader) error {
+	script, err := io.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+	if err := runScript(context.Background(), m.conn, string(script)); err != nil {
+		return database.Error{OrigErr: err, Err: "migration failed", Query: script}
+	}
+	return nil
+}
+
+func (m *Migrate) SetVersion(version int, dirty bool) error {
+	ctx := context.Background()
+	table := quoteName(m.config.MigrationsTable)
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE TRUE;"); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to set version %d: %w", version, err)
+	}
+	// As with other drivers, the nil version is only recorded if it's dirty.
+	if version >= 0 || (version == database.NilVersion && dirty) {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO "+table+" (version, dirty) VALUES (?, ?);",
+			version, dirty,
+		); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to set version %d: %w", version, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (m *Migrate) Version() (int, bool, error) {
+	var version int
+	var dirty bool
+	err := m.conn.QueryRowContext(context.Background(),
+		"SELECT version, dirty FROM "+quoteName(m.config.MigrationsTable)+" LIMIT 1;",
+	).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.NilVersion, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
+
+// Drop drops all the tables and views in the default dataset, except for the
+// lock table (which is held while dropping).
+func (m *Migrate) Drop() error {
+	ctx := context.Background()
+
+	var project, dataset string
+	if err := m.conn.QueryRowContext(ctx,
+		"SELECT @@dataset_project_id, @@dataset_id;",
+	).Scan(&project, &dataset); err != nil {
+		return fmt.Errorf("failed to get default dataset: %w", err)
+	}
+	datasetName := quoteName(project + "." + dataset)
+
+	rows, err := m.conn.QueryContext(ctx,
+		"SELECT table_name, table_type FROM "+datasetName+".INFORMATION_SCHEMA.TABLES;",
+	)
+	if err != nil {
+		return err
+	}
+	type table struct{ name, tableType string }
+	var tables []table
+	for rows.Next() {
+		var t table
+		if err := rows.Scan(&t.name, &t.tableType); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	lockTable := m.config.LockTable[strings.LastIndex(m.config.LockTable, ".")+1:]
+	for _, t := range tables {
+		if t.name == lockTable {
+			continue
+		}
+		var kind string
+		switch t.tableType {
+		case "VIEW":
+			kind = "VIEW"
+		case "MATERIALIZED VIEW":
+			kind = "MATERIALIZED VIEW"
+		case "EXTERNAL":
+			kind = "EXTERNAL TABLE"
+		case "SNAPSHOT":
+			kind = "SNAPSHOT TABLE"
+		default:
+			kind = "TABLE"
+		}
+		if _, err := m.conn.ExecContext(ctx,
+			"DROP "+kind+" IF EXISTS "+datasetName+"."+quoteName(t.name)+";",
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
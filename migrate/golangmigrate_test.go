@@ -0,0 +1,158 @@
+package migrate
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// Simulates the lock and version tables of the golang-migrate driver.
+type fakeMigrateTables struct {
+	lock fakeLockTable
+	// The rows of the version table, as (version, dirty) strings.
+	versions [][]any
+}
+
+func (m *fakeMigrateTables) handle(q fakeQuery) fakeResult {
+	if result, ok := m.lock.handle(q); ok {
+		return result
+	}
+	switch {
+	case strings.HasPrefix(q.SQL, "DELETE FROM `schema_migrations`"):
+		affected := int64(len(m.versions))
+		m.versions = nil
+		return fakeResult{DMLAffected: affected}
+	case strings.HasPrefix(q.SQL, "INSERT INTO `schema_migrations`"):
+		m.versions = append(m.versions, []any{q.Args[0], q.Args[1]})
+		return fakeResult{DMLAffected: 1}
+	case strings.HasPrefix(q.SQL, "SELECT version, dirty FROM"):
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "version", Type: "INTEGER"}, {Name: "dirty", Type: "BOOLEAN"}},
+			Rows:   m.versions,
+		}
+	case strings.Contains(q.SQL, "fail"):
+		return fakeResult{Err: &bq.ErrorProto{Reason: "invalidQuery", Message: "Syntax error"}}
+	}
+	return fakeResult{}
+}
+
+func TestMigrate(t *testing.T) {
+	tables := &fakeMigrateTables{}
+	server := newFakeServer(t, tables.handle)
+	db := server.open()
+
+	driver, err := WithInstance(db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Close()
+	statements := server.takeSQL()
+	if len(statements) != 3 ||
+		!strings.HasPrefix(statements[0], "CREATE TABLE IF NOT EXISTS `schema_migrations` ") ||
+		!strings.HasPrefix(statements[1], "CREATE TABLE IF NOT EXISTS `schema_migrations_lock` ") {
+		t.Errorf("WithInstance ran %q", statements)
+	}
+
+	other, err := WithInstance(db, &Config{MigrationsTable: "schema_migrations"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+
+	// Only one driver can hold the lock at a time.
+	if err := driver.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Lock(); !errors.Is(err, database.ErrLocked) {
+		t.Errorf("Lock() while locked = %v, want ErrLocked", err)
+	}
+	if err := other.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if tables.lock.owner == "" {
+		t.Fatal("lock released by another driver")
+	}
+
+	if version, dirty, err := driver.Version(); err != nil || version != database.NilVersion || dirty {
+		t.Errorf("Version() = %d, %v, %v, want NilVersion", version, dirty, err)
+	}
+	server.takeSQL()
+
+	if err := driver.Run(strings.NewReader("INSERT INTO t VALUES (1);")); err != nil {
+		t.Fatal(err)
+	}
+	if statements := server.takeSQL(); len(statements) != 3 || statements[0] != "BEGIN TRANSACTION;" {
+		t.Errorf("Run ran %q, want a transaction", statements)
+	}
+	var migrationErr database.Error
+	if err := driver.Run(strings.NewReader("CREATE TABLE fail (x INT64);")); !errors.As(err, &migrationErr) ||
+		migrationErr.Query == nil {
+		t.Errorf("Run() of a failing migration = %v, want a database.Error", err)
+	}
+
+	for _, test := range []struct {
+		version int
+		dirty   bool
+	}{
+		{3, true},
+		{3, false},
+		{database.NilVersion, true},
+	} {
+		if err := driver.SetVersion(test.version, test.dirty); err != nil {
+			t.Fatal(err)
+		}
+		if version, dirty, err := driver.Version(); err != nil || version != test.version || dirty != test.dirty {
+			t.Errorf("Version() = %d, %v, %v, want %d, %v", version, dirty, err, test.version, test.dirty)
+		}
+	}
+	// The nil version is only recorded if it's dirty.
+	if err := driver.SetVersion(database.NilVersion, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(tables.versions) != 0 {
+		t.Errorf("SetVersion(NilVersion, false) recorded %v", tables.versions)
+	}
+
+	if err := driver.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Lock(); err != nil {
+		t.Errorf("Lock() after Unlock() = %v", err)
+	}
+}
+
+func TestMigrateOpen(t *testing.T) {
+	tables := &fakeMigrateTables{}
+	server := newFakeServer(t, tables.handle)
+
+	query := url.Values{
+		"endpoint":           {server.URL + "/"},
+		"disableAuth":        {"true"},
+		"x-migrations-table": {"dataset.versions"},
+		"x-lock-table":       {"locks"},
+	}
+	driver, err := (&Migrate{}).Open("bigquery://project/dataset?" + query.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the driver also aborts the session of its connection.
+	statements := server.takeSQL()
+	if len(statements) != 5 ||
+		!strings.HasPrefix(statements[0], "CREATE TABLE IF NOT EXISTS `dataset`.`versions` ") ||
+		!strings.HasPrefix(statements[1], "CREATE TABLE IF NOT EXISTS `locks` ") ||
+		!strings.HasPrefix(statements[3], "UPDATE `locks` SET owner = ?") ||
+		statements[4] != "CALL BQ.ABORT_SESSION();" {
+		t.Errorf("ran %q", statements)
+	}
+}
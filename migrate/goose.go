@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	goosedb "github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/database/dialect"
+	"github.com/pressly/goose/v3/lock"
+)
+
+var (
+	_ dialect.Querier    = GooseQuerier{}
+	_ lock.SessionLocker = (*GooseLocker)(nil)
+)
+
+// DefaultGooseTable is the default name of the goose version table.
+const DefaultGooseTable = "goose_db_version"
+
+// How often a goose locker retries acquiring a lock held by another process.
+const gooseLockRetryInterval = 5 * time.Second
+
+// GooseQuerier is a goose dialect for BigQuery, whose version table is resolved
+// relative to the default dataset of the connection (unless qualified with a
+// dataset). As BigQuery has no auto-incrementing columns, the IDs of version
+// table rows are assigned by the INSERT statement (which is safe as long as
+// migrations are locked, see [GooseLocker]).
+type GooseQuerier struct{}
+
+// NewGooseStore returns a goose store for the version table with the given
+// name, which can be passed to [goose.NewProvider] with [goose.WithStore] (and
+// [goose.DialectCustom]).
+//
+// [goose.NewProvider]: https://pkg.go.dev/github.com/pressly/goose/v3#NewProvider
+// [goose.WithStore]: https://pkg.go.dev/github.com/pressly/goose/v3#WithStore
+// [goose.DialectCustom]: https://pkg.go.dev/github.com/pressly/goose/v3#DialectCustom
+func NewGooseStore(tableName string) (goosedb.Store, error) {
+	return goosedb.NewStoreFromQuerier(tableName, GooseQuerier{})
+}
+
+func (GooseQuerier) CreateTable(tableName string) string {
+	return "CREATE TABLE IF NOT EXISTS " + quoteName(tableName) +
+		" (id INT64 NOT NULL, version_id INT64 NOT NULL, is_applied BOOL NOT NULL, tstamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP());"
+}
+
+func (GooseQuerier) InsertVersion(tableName string) string {
+	return "INSERT INTO " + quoteName(tableName) + " (id, version_id, is_applied, tstamp)" +
+		" SELECT COALESCE(MAX(id), 0) + 1, ?, ?, CURRENT_TIMESTAMP() FROM " + quoteName(tableName) + ";"
+}
+
+func (GooseQuerier) DeleteVersion(tableName string) string {
+	return "DELETE FROM " + quoteName(tableName) + " WHERE version_id = ?;"
+}
+
+func (GooseQuerier) GetMigrationByVersion(tableName string) string {
+	return "SELECT tstamp, is_applied FROM " + quoteName(tableName) + " WHERE version_id = ? ORDER BY tstamp DESC LIMIT 1;"
+}
+
+func (GooseQuerier) ListMigrations(tableName string) string {
+	return "SELECT version_id, is_applied FROM " + quoteName(tableName) + " ORDER BY id DESC;"
+}
+
+func (GooseQuerier) GetLatestVersion(tableName string) string {
+	return "SELECT MAX(version_id) FROM " + quoteName(tableName) + ";"
+}
+
+// GooseLocker is a goose session locker (see [goose.WithSessionLocker]),
+// which emulates an advisory lock with a lock table, like the golang-migrate
+// driver. SessionLock waits for a lock held by another process to be released
+// until its context is done.
+//
+// [goose.WithSessionLocker]: https://pkg.go.dev/github.com/pressly/goose/v3#WithSessionLocker
+type GooseLocker struct {
+	lock *tableLock
+}
+
+// NewGooseLocker returns a locker using the lock table with the given name
+// (which is created if it doesn't exist), e.g. DefaultGooseTable + "_lock".
+func NewGooseLocker(tableName string) *GooseLocker {
+	return &GooseLocker{lock: newTableLock(tableName)}
+}
+
+func (l *GooseLocker) SessionLock(ctx context.Context, conn *sql.Conn) error {
+	if err := l.lock.create(ctx, conn); err != nil {
+		return err
+	}
+	for {
+		locked, err := l.lock.tryLock(ctx, conn)
+		if err != nil || locked {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(gooseLockRetryInterval):
+		}
+	}
+}
+
+func (l *GooseLocker) SessionUnlock(ctx context.Context, conn *sql.Conn) error {
+	return l.lock.unlock(ctx, conn)
+}
@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	goosedb "github.com/pressly/goose/v3/database"
+)
+
+func TestGooseStore(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult { return fakeResult{DMLAffected: 1} })
+	conn := server.conn()
+	ctx := context.Background()
+
+	store, err := NewGooseStore("dataset." + DefaultGooseTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateVersionTable(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Insert(ctx, conn, goosedb.InsertRequest{Version: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, conn, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := server.takeQueries()
+	if len(queries) != 3 {
+		t.Fatalf("got %d queries, want 3", len(queries))
+	}
+	table := "`dataset`.`goose_db_version`"
+	if !strings.HasPrefix(queries[0].SQL, "CREATE TABLE IF NOT EXISTS "+table+" ") {
+		t.Errorf("CreateVersionTable ran %q", queries[0].SQL)
+	}
+	if q := queries[1]; !strings.HasPrefix(q.SQL, "INSERT INTO "+table+" ") ||
+		len(q.Args) != 2 || q.Args[0] != "7" || q.Args[1] != "true" {
+		t.Errorf("Insert ran %q with %q", q.SQL, q.Args)
+	}
+	if q := queries[2]; q.SQL != "DELETE FROM "+table+" WHERE version_id = ?;" || len(q.Args) != 1 || q.Args[0] != "7" {
+		t.Errorf("Delete ran %q with %q", q.SQL, q.Args)
+	}
+}
+
+func TestGooseLocker(t *testing.T) {
+	table := &fakeLockTable{}
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		result, _ := table.handle(q)
+		return result
+	})
+	conn := server.conn()
+	ctx := context.Background()
+
+	locker, other := NewGooseLocker("locks"), NewGooseLocker("locks")
+	if err := locker.SessionLock(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another locker waits for the lock until its context is done.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := other.SessionLock(timeoutCtx, conn); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SessionLock() while locked = %v, want a deadline error", err)
+	}
+
+	if err := locker.SessionUnlock(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.SessionLock(ctx, conn); err != nil {
+		t.Errorf("SessionLock() after SessionUnlock() = %v", err)
+	}
+	if table.owner != other.lock.owner {
+		t.Errorf("lock owned by %q, want %q", table.owner, other.lock.owner)
+	}
+}
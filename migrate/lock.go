@@ -0,0 +1,124 @@
+package migrate
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	bq "cloud.google.com/go/bigquery"
+	bigquery "github.com/timescale/bigquery-go-client"
+	"google.golang.org/api/googleapi"
+)
+
+// The subset of [sql.DB], [sql.Conn] and [sql.Tx] methods used to run
+// statements.
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Emulates an advisory lock (which BigQuery doesn't have) with a lock table
+// holding a single row, which records the current owner of the lock. The lock
+// is acquired and released with conditional UPDATE statements, which BigQuery
+// doesn't run concurrently on the same table: of two concurrent attempts to
+// acquire the lock, one either finds it taken or fails with a concurrent
+// update error.
+type tableLock struct {
+	table string
+	owner string
+}
+
+func newTableLock(table string) *tableLock {
+	return &tableLock{
+		table: quoteName(table),
+		owner: newOwner(),
+	}
+}
+
+// Creates the lock table and its row, if they don't exist yet.
+func (l *tableLock) create(ctx context.Context, db execQuerier) error {
+	if _, err := db.ExecContext(ctx,
+		"CREATE TABLE IF NOT EXISTS "+l.table+" (id INT64 NOT NULL, owner STRING, acquired_at TIMESTAMP);",
+	); err != nil {
+		return fmt.Errorf("failed to create lock table %s: %w", l.table, err)
+	}
+	if _, err := db.ExecContext(ctx,
+		"MERGE "+l.table+" AS target USING (SELECT 1 AS id) AS source ON target.id = source.id "+
+			"WHEN NOT MATCHED THEN INSERT (id) VALUES (source.id);",
+	); err != nil {
+		return fmt.Errorf("failed to initialize lock table %s: %w", l.table, err)
+	}
+	return nil
+}
+
+// Attempts to acquire the lock, and reports whether it was acquired.
+func (l *tableLock) tryLock(ctx context.Context, db execQuerier) (bool, error) {
+	var stats bigquery.JobStats
+	if _, err := db.ExecContext(ctx,
+		"UPDATE "+l.table+" SET owner = ?, acquired_at = CURRENT_TIMESTAMP() WHERE id = 1 AND owner IS NULL;",
+		l.owner, bigquery.CollectStats(&stats),
+	); err != nil {
+		if concurrentUpdateError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lock %s: %w", l.table, err)
+	}
+	return stats.NumDMLAffectedRows > 0, nil
+}
+
+// Releases the lock, if it's held by this owner.
+func (l *tableLock) unlock(ctx context.Context, db execQuerier) error {
+	if _, err := db.ExecContext(ctx,
+		"UPDATE "+l.table+" SET owner = NULL, acquired_at = NULL WHERE id = 1 AND owner = ?;",
+		l.owner,
+	); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.table, err)
+	}
+	return nil
+}
+
+// Returns a random owner ID, which identifies the holder of a lock.
+func newOwner() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Reports whether the error is caused by a conflicting concurrent DML
+// statement. BigQuery reports these as invalidQuery errors ("Could not
+// serialize access to table ... due to concurrent update"), either as the
+// error of the job, or of the API request.
+func concurrentUpdateError(err error) bool {
+	isConflict := func(reason, message string) bool {
+		return reason == "invalidQuery" && strings.Contains(message, "due to concurrent update")
+	}
+
+	var jobErr *bq.Error
+	if errors.As(err, &jobErr) && isConflict(jobErr.Reason, jobErr.Message) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+		for _, item := range apiErr.Errors {
+			if isConflict(item.Reason, item.Message) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Quotes each part of a (possibly dataset-qualified) table name with
+// backticks.
+func quoteName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = "`" + strings.ReplaceAll(part, "`", "\\`") + "`"
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,169 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// The error BigQuery fails conflicting DML statements with.
+var concurrentUpdate = &bq.ErrorProto{
+	Reason:  "invalidQuery",
+	Message: "Could not serialize access to table project:dataset.locks due to concurrent update",
+}
+
+// Simulates the row of a lock table (see tableLock).
+type fakeLockTable struct {
+	owner string
+	// The number of attempts to acquire the lock that fail with a concurrent
+	// update error.
+	conflicts int
+}
+
+// Runs the lock statements, and reports whether q is one.
+func (l *fakeLockTable) handle(q fakeQuery) (fakeResult, bool) {
+	switch {
+	case strings.Contains(q.SQL, "SET owner = ?"):
+		if l.conflicts > 0 {
+			l.conflicts--
+			return fakeResult{Err: concurrentUpdate}, true
+		}
+		if l.owner != "" {
+			return fakeResult{}, true
+		}
+		l.owner = q.Args[0]
+		return fakeResult{DMLAffected: 1}, true
+	case strings.Contains(q.SQL, "SET owner = NULL"):
+		if l.owner != q.Args[0] {
+			return fakeResult{}, true
+		}
+		l.owner = ""
+		return fakeResult{DMLAffected: 1}, true
+	}
+	return fakeResult{}, false
+}
+
+func TestTableLock(t *testing.T) {
+	table := &fakeLockTable{}
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		result, _ := table.handle(q)
+		if strings.HasPrefix(q.SQL, "UPDATE `dataset`.`broken`") {
+			return fakeResult{Err: &bq.ErrorProto{Reason: "invalidQuery", Message: "Syntax error"}}
+		}
+		return result
+	})
+	conn := server.conn()
+	ctx := context.Background()
+
+	l1, l2 := newTableLock("dataset.locks"), newTableLock("dataset.locks")
+	if err := l1.create(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	statements := server.takeSQL()
+	if len(statements) != 2 ||
+		!strings.HasPrefix(statements[0], "CREATE TABLE IF NOT EXISTS `dataset`.`locks` ") ||
+		!strings.HasPrefix(statements[1], "MERGE `dataset`.`locks` ") {
+		t.Errorf("create ran %q", statements)
+	}
+
+	tryLock := func(l *tableLock, want bool) {
+		t.Helper()
+		if locked, err := l.tryLock(ctx, conn); err != nil || locked != want {
+			t.Fatalf("tryLock() = %v, %v, want %v", locked, err, want)
+		}
+	}
+	tryLock(l1, true)
+	tryLock(l2, false)
+	tryLock(l1, false)
+
+	// Only the owner can release the lock.
+	if err := l2.unlock(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	if table.owner != l1.owner {
+		t.Fatalf("lock released by another owner")
+	}
+	if err := l1.unlock(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	tryLock(l2, true)
+	if err := l2.unlock(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	// A concurrent attempt to acquire the lock means it's taken.
+	table.conflicts = 1
+	tryLock(l1, false)
+	tryLock(l1, true)
+
+	if locked, err := newTableLock("dataset.broken").tryLock(ctx, conn); err == nil {
+		t.Errorf("tryLock() with a failing statement = %v, want an error", locked)
+	}
+}
+
+func TestConcurrentUpdateError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			"job error",
+			fmt.Errorf("query failed: %w", &bigquery.Error{Reason: concurrentUpdate.Reason, Message: concurrentUpdate.Message}),
+			true,
+		},
+		{
+			"API error",
+			&googleapi.Error{
+				Code:   http.StatusBadRequest,
+				Errors: []googleapi.ErrorItem{{Reason: concurrentUpdate.Reason, Message: concurrentUpdate.Message}},
+			},
+			true,
+		},
+		{
+			"other job error",
+			&bigquery.Error{Reason: "invalidQuery", Message: "Syntax error: Unexpected identifier"},
+			false,
+		},
+		{
+			"other reason",
+			&bigquery.Error{Reason: "backendError", Message: concurrentUpdate.Message},
+			false,
+		},
+		{
+			"other API error",
+			&googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: concurrentUpdate.Reason, Message: concurrentUpdate.Message}},
+			},
+			false,
+		},
+		{"unstructured error", errors.New(concurrentUpdate.Message), false},
+	}
+	for _, test := range tests {
+		if got := concurrentUpdateError(test.err); got != test.want {
+			t.Errorf("%s: concurrentUpdateError() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestQuoteName(t *testing.T) {
+	tests := map[string]string{
+		"locks":                 "`locks`",
+		"dataset.locks":         "`dataset`.`locks`",
+		"project.dataset.locks": "`project`.`dataset`.`locks`",
+		"odd`name":              "`odd\\`name`",
+	}
+	for name, want := range tests {
+		if got := quoteName(name); got != want {
+			t.Errorf("quoteName(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
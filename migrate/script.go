@@ -0,0 +1,128 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"unicode"
+)
+
+// The statements which BigQuery allows in multi-statement transactions (other
+// than DDL on temporary tables, which migrations have little use for).
+var transactionalStatements = map[string]bool{
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"MERGE":   true,
+	"SELECT":  true,
+	"WITH":    true,
+	"DECLARE": true,
+	"SET":     true,
+}
+
+// Runs a (possibly multi-statement) migration script as a single job in the
+// session of conn. If BigQuery allows all of its statements in a transaction,
+// the script is run in one, so that it's applied atomically.
+func runScript(ctx context.Context, conn *sql.Conn, script string) error {
+	// BigQuery rejects empty queries, e.g. migrations with only comments.
+	if strings.TrimSpace(strings.Join(splitStatements(script), "")) == "" {
+		return nil
+	}
+	if !transactional(script) {
+		_, err := conn.ExecContext(ctx, script)
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Reports whether all the statements of the script may be run in a
+// transaction. Scripts that manage their own transactions (or use BEGIN ...
+// END blocks) aren't.
+func transactional(script string) bool {
+	statements := 0
+	for _, statement := range splitStatements(script) {
+		statement = strings.TrimLeft(statement, " \t\r\n(")
+		end := strings.IndexFunc(statement, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		if end < 0 {
+			end = len(statement)
+		}
+		keyword := strings.ToUpper(statement[:end])
+		if keyword == "" {
+			continue
+		}
+		if !transactionalStatements[keyword] {
+			return false
+		}
+		statements++
+	}
+	return statements > 0
+}
+
+// Splits a script into its statements, with comments removed and string
+// literals and quoted identifiers left intact.
+func splitStatements(script string) []string {
+	var statements []string
+	var b strings.Builder
+	for i := 0; i < len(script); {
+		rest := script[i:]
+		switch {
+		case strings.HasPrefix(rest, "--"), rest[0] == '#':
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			b.WriteByte(' ')
+			i += end + 1
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				end = len(rest) - 4
+			}
+			b.WriteByte(' ')
+			i += end + 4
+		case strings.HasPrefix(rest, `'''`), strings.HasPrefix(rest, `"""`):
+			end := quotedEnd(rest, 3, rest[:3])
+			b.WriteString(rest[:end])
+			i += end
+		case rest[0] == '\'', rest[0] == '"', rest[0] == '`':
+			end := quotedEnd(rest, 1, rest[:1])
+			b.WriteString(rest[:end])
+			i += end
+		case rest[0] == ';':
+			statements = append(statements, b.String())
+			b.Reset()
+			i++
+		default:
+			b.WriteByte(rest[0])
+			i++
+		}
+	}
+	return append(statements, b.String())
+}
+
+// Returns the end of the quoted literal starting at s, whose contents start
+// at i.
+func quotedEnd(s string, i int, quote string) int {
+	for i < len(s) {
+		switch {
+		case s[i] == '\\':
+			i += 2
+		case strings.HasPrefix(s[i:], quote):
+			return i + len(quote)
+		default:
+			i++
+		}
+	}
+	return len(s)
+}
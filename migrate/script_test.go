@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"", []string{""}},
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", " SELECT 2", ""}},
+		{`SELECT ';', "a;b", ` + "`c;d`", []string{`SELECT ';', "a;b", ` + "`c;d`"}},
+		{"SELECT '''x;\n'y''';", []string{"SELECT '''x;\n'y'''", ""}},
+		{`SELECT 'it\'s;', "\";"`, []string{`SELECT 'it\'s;', "\";"`}},
+		{"-- a; b\nSELECT 1 # c; d\n; /* e; f */", []string{" SELECT 1  ", "  "}},
+		{"SELECT 1 -- unterminated; comment", []string{"SELECT 1  "}},
+		{"SELECT 1 /* unterminated; comment", []string{"SELECT 1  "}},
+		{
+			"BEGIN\n  INSERT INTO t VALUES (1);\n  SELECT ';';\nEND;",
+			[]string{"BEGIN\n  INSERT INTO t VALUES (1)", "\n  SELECT ';'", "\nEND", ""},
+		},
+	}
+	for _, test := range tests {
+		if got := splitStatements(test.script); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitStatements(%q) = %q, want %q", test.script, got, test.want)
+		}
+	}
+}
+
+func TestTransactional(t *testing.T) {
+	tests := []struct {
+		script string
+		want   bool
+	}{
+		{"INSERT INTO t VALUES (1); UPDATE t SET x = 2 WHERE true;", true},
+		{"DECLARE x INT64 DEFAULT 1; SET x = 2; DELETE FROM t WHERE x = 1;", true},
+		{"(SELECT 1); WITH a AS (SELECT 1) SELECT * FROM a;", true},
+		{"-- CREATE TABLE t (x INT64);\nmerge t USING s ON true WHEN MATCHED THEN DELETE;", true},
+		{"INSERT INTO t VALUES ('CREATE TABLE u (x INT64); DROP TABLE t;');", true},
+		{"CREATE TABLE t (x INT64);", false},
+		{"INSERT INTO t VALUES (1); ALTER TABLE t ADD COLUMN y INT64;", false},
+		{"BEGIN TRANSACTION; INSERT INTO t VALUES (1); COMMIT TRANSACTION;", false},
+		{"BEGIN\n  INSERT INTO t VALUES (1);\nEND;", false},
+		{"-- only a comment", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := transactional(test.script); got != test.want {
+			t.Errorf("transactional(%q) = %v, want %v", test.script, got, test.want)
+		}
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		if strings.Contains(q.SQL, "fail") {
+			return fakeResult{Err: &bq.ErrorProto{Reason: "invalidQuery", Message: "Syntax error"}}
+		}
+		return fakeResult{}
+	})
+	conn := server.conn()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr bool
+		want    []string
+	}{
+		{
+			"transactional",
+			"INSERT INTO t VALUES (1); UPDATE t SET x = 2 WHERE true;",
+			false,
+			[]string{"BEGIN TRANSACTION;", "INSERT INTO t VALUES (1); UPDATE t SET x = 2 WHERE true;", "COMMIT TRANSACTION;"},
+		},
+		{
+			"failed transactional",
+			"INSERT INTO fail VALUES (1);",
+			true,
+			[]string{"BEGIN TRANSACTION;", "INSERT INTO fail VALUES (1);", "ROLLBACK TRANSACTION;"},
+		},
+		{
+			"non-transactional",
+			"CREATE TABLE t (x INT64); INSERT INTO t VALUES (1);",
+			false,
+			[]string{"CREATE TABLE t (x INT64); INSERT INTO t VALUES (1);"},
+		},
+		{
+			"failed non-transactional",
+			"CREATE TABLE fail (x INT64);",
+			true,
+			[]string{"CREATE TABLE fail (x INT64);"},
+		},
+		{"empty", "-- nothing to do\n;\n", false, nil},
+	}
+	for _, test := range tests {
+		err := runScript(ctx, conn, test.script)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: runScript() = %v, want an error: %v", test.name, err, test.wantErr)
+		}
+		if got := server.takeSQL(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ran %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	bigquery "github.com/timescale/bigquery-go-client"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// A query received by the fake server.
+type fakeQuery struct {
+	SQL string
+	// Args are the values of the positional parameters.
+	Args      []string
+	SessionID string
+}
+
+// The result of a query run by the fake server.
+type fakeResult struct {
+	Schema      []*bq.TableFieldSchema
+	Rows        [][]any
+	DMLAffected int64
+	// Err, if set, fails the job.
+	Err *bq.ErrorProto
+}
+
+// A fake BigQuery API server, which records the queries it receives, and
+// returns the results of the handler for them. Jobs are run in the session
+// "session" when a session is requested.
+type fakeServer struct {
+	*httptest.Server
+	t       *testing.T
+	handler func(q fakeQuery) fakeResult
+
+	mu      sync.Mutex
+	queries []fakeQuery
+	jobs    map[string]*bq.Job
+	results map[string]fakeResult
+}
+
+var (
+	insertJobPath = regexp.MustCompile(`/projects/[^/]+/jobs$`)
+	getJobPath    = regexp.MustCompile(`/projects/[^/]+/jobs/([^/]+)$`)
+	queryPath     = regexp.MustCompile(`/projects/[^/]+/queries/([^/]+)$`)
+)
+
+func newFakeServer(t *testing.T, handler func(q fakeQuery) fakeResult) *fakeServer {
+	s := &fakeServer{
+		t:       t,
+		handler: handler,
+		jobs:    map[string]*bq.Job{},
+		results: map[string]fakeResult{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Opens a database connected to the fake server, with "dataset" as the
+// default dataset.
+func (s *fakeServer) open() *sql.DB {
+	db := sql.OpenDB(bigquery.NewConnector(bigquery.Config{
+		ProjectID:   "project",
+		Dataset:     "dataset",
+		Endpoint:    s.URL + "/",
+		DisableAuth: true,
+	}))
+	s.t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// Opens a connection to the fake server (see fakeServer.open).
+func (s *fakeServer) conn() *sql.Conn {
+	s.t.Helper()
+	conn, err := s.open().Conn(context.Background())
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Returns the queries received so far, and forgets them.
+func (s *fakeServer) takeQueries() []fakeQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queries := s.queries
+	s.queries = nil
+	return queries
+}
+
+// Returns the SQL of the queries received so far, and forgets them.
+func (s *fakeServer) takeSQL() []string {
+	var statements []string
+	for _, q := range s.takeQueries() {
+		statements = append(statements, q.SQL)
+	}
+	return statements
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var response any
+	switch {
+	case r.Method == http.MethodPost && insertJobPath.MatchString(r.URL.Path):
+		var job bq.Job
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response = s.insertJob(&job)
+	case r.Method == http.MethodGet && getJobPath.MatchString(r.URL.Path):
+		job, ok := s.jobs[getJobPath.FindStringSubmatch(r.URL.Path)[1]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		response = job
+	case r.Method == http.MethodGet && queryPath.MatchString(r.URL.Path):
+		jobID := queryPath.FindStringSubmatch(r.URL.Path)[1]
+		job, ok := s.jobs[jobID]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		response = queryResults(job, s.results[jobID], r.URL.Query().Get("maxResults") == "0")
+	default:
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (s *fakeServer) insertJob(job *bq.Job) *bq.Job {
+	config := job.Configuration.Query
+	query := fakeQuery{SQL: config.Query}
+	for _, param := range config.QueryParameters {
+		query.Args = append(query.Args, param.ParameterValue.Value)
+	}
+	for _, property := range config.ConnectionProperties {
+		if property.Key == "session_id" {
+			query.SessionID = property.Value
+		}
+	}
+	s.queries = append(s.queries, query)
+
+	result := s.handler(query)
+	statementType, _, _ := strings.Cut(strings.TrimSpace(query.SQL), " ")
+	job.Status = &bq.JobStatus{State: "DONE", ErrorResult: result.Err}
+	job.Statistics = &bq.JobStatistics{
+		Query: &bq.JobStatistics2{
+			StatementType:      strings.ToUpper(statementType),
+			NumDmlAffectedRows: result.DMLAffected,
+		},
+	}
+	if config.CreateSession {
+		job.Statistics.SessionInfo = &bq.SessionInfo{SessionId: "session"}
+	} else if query.SessionID != "" {
+		job.Statistics.SessionInfo = &bq.SessionInfo{SessionId: query.SessionID}
+	}
+	s.jobs[job.JobReference.JobId] = job
+	s.results[job.JobReference.JobId] = result
+	return job
+}
+
+func queryResults(job *bq.Job, result fakeResult, schemaOnly bool) *bq.GetQueryResultsResponse {
+	response := &bq.GetQueryResultsResponse{
+		JobReference:       job.JobReference,
+		JobComplete:        true,
+		TotalRows:          uint64(len(result.Rows)),
+		NumDmlAffectedRows: result.DMLAffected,
+	}
+	// Like BigQuery, report the affected rows of DML statements as the total.
+	if result.DMLAffected > 0 {
+		response.TotalRows = uint64(result.DMLAffected)
+	}
+	if result.Schema != nil {
+		response.Schema = &bq.TableSchema{Fields: result.Schema}
+	}
+	if !schemaOnly {
+		for _, row := range result.Rows {
+			cells := make([]*bq.TableCell, len(row))
+			for i, value := range row {
+				cells[i] = &bq.TableCell{V: value}
+			}
+			response.Rows = append(response.Rows, &bq.TableRow{F: cells})
+		}
+	}
+	return response
+}
@@ -1,6 +1,8 @@
 package bigquery
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 
 	"cloud.google.com/go/bigquery"
@@ -18,23 +20,66 @@ type GetJob func(job *bigquery.Job)
 // [bigquery.Query] value before the Query/Exec method returns.
 type GetQuery func(query *bigquery.Query)
 
+// The options passed as arguments to a Query or Exec method, which apply to
+// that statement only.
 type options struct {
-	getQuery GetQuery
-	getJob   GetJob
+	getQuery    GetQuery
+	getJob      GetJob
+	destination *Destination
+	external    ExternalTables
 }
 
-func (o *options) CheckNamedValue(named *driver.NamedValue) error {
-	switch value := named.Value.(type) {
-	case GetQuery:
-		o.getQuery = value
-		return driver.ErrRemoveArgument
-	case GetJob:
-		o.getJob = value
-		return driver.ErrRemoveArgument
+func (c *conn) CheckNamedValue(named *driver.NamedValue) error {
+	switch named.Value.(type) {
+	case GetQuery, GetJob, Destination, *Destination, ExternalTables:
+		// Options are extracted from the arguments when the statement is
+		// executed (see extractOptions).
+		return nil
+	case sql.Out:
+		// OUT/INOUT parameters are handled when the statement is executed.
+		return nil
+	}
+	if checkGeographyValue(named) || checkRangeValue(named) {
+		return nil
+	}
+	if ok, err := checkJSONValue(named); ok {
+		return err
 	}
 	return driver.ErrSkip
 }
 
+// Splits the options from the query arguments.
+func extractOptions(args []driver.NamedValue) (options, []driver.NamedValue) {
+	var opts options
+	var rest []driver.NamedValue
+	for _, arg := range args {
+		switch value := arg.Value.(type) {
+		case GetQuery:
+			opts.getQuery = value
+		case GetJob:
+			opts.getJob = value
+		case Destination:
+			opts.destination = &value
+		case *Destination:
+			opts.destination = value
+		case ExternalTables:
+			opts.external = mergeExternalTables(opts.external, value)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return opts, rest
+}
+
+// Returns the destination passed as an argument, or else the one attached to
+// the context (if any).
+func (o options) destinationOrContext(ctx context.Context) *Destination {
+	if o.destination != nil {
+		return o.destination
+	}
+	return destinationFromContext(ctx)
+}
+
 func (o *options) getQueryOpt(query *bigquery.Query) {
 	if o.getQuery != nil {
 		o.getQuery(query)
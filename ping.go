@@ -0,0 +1,91 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// PingMode selects how thoroughly connections are checked by Ping.
+type PingMode string
+
+const (
+	// PingModeLight (the default) performs the cheapest check available: it
+	// runs a query in the connection's session (if any, to check that the
+	// session is still alive), and otherwise fetches the default dataset's
+	// metadata (if any), or dry runs a query.
+	PingModeLight PingMode = "light"
+
+	// PingModeFull fetches the default dataset's metadata (if any) and runs a
+	// query (in the connection's session, if any), which checks both
+	// authentication and the permission to run queries.
+	PingModeFull PingMode = "full"
+)
+
+func parsePingMode(mode string) (PingMode, error) {
+	switch m := PingMode(strings.ToLower(mode)); m {
+	case "", PingModeLight, PingModeFull:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid ping mode: %s", mode)
+	}
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if !c.IsValid() {
+		return driver.ErrBadConn
+	}
+
+	if c.config.PingMode == PingModeFull {
+		if c.config.Dataset != "" {
+			if _, err := c.dataset().Metadata(ctx); err != nil {
+				return err
+			}
+		}
+		return c.pingQuery(ctx, false)
+	}
+
+	switch {
+	case c.sessionID != "":
+		return c.pingQuery(ctx, false)
+	case c.config.Dataset != "":
+		_, err := c.dataset().Metadata(ctx)
+		return err
+	default:
+		return c.pingQuery(ctx, true)
+	}
+}
+
+// Runs (or dry runs) a trivial query, in the connection's session if any.
+// Doesn't create a session, and bypasses the interceptors (but not the job
+// limiter).
+func (c *conn) pingQuery(ctx context.Context, dryRun bool) error {
+	s := &stmt{conn: c, query: "SELECT 1", internal: true}
+	query := c.client.Query(s.query)
+	query.DryRun = dryRun
+	query.ConnectionProperties = s.buildConnectionProperties()
+
+	// Unlike light pings, full pings count towards the concurrent job limits.
+	job, err := c.limiter.wrap(runQuery)(ctx, query)
+	if job != nil && !dryRun {
+		c.updateSession(getSessionID(job))
+	}
+	if err == nil && !dryRun {
+		var it *bigquery.RowIterator
+		if it, err = job.Read(ctx); err == nil {
+			var row []bigquery.Value
+			err = it.Next(&row)
+		}
+	}
+	if err != nil {
+		s.checkSessionError(err)
+		if c.invalid {
+			return driver.ErrBadConn
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// JobStatsByID returns the statistics of the job with the given ID (in the
+// location configured for db), e.g. to render the query plan of a job run
+// elsewhere.
+func JobStatsByID(ctx context.Context, db *sql.DB, jobID string) (*JobStats, error) {
+	sqlConn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sqlConn.Close()
+
+	var job *bigquery.Job
+	if err := sqlConn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*conn)
+		if !ok {
+			return errors.New("not a BigQuery connection")
+		}
+		job, err = c.client.JobFromIDLocation(ctx, jobID, c.config.Location)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return jobStats(job)
+}
+
+// PlanText renders the job's query plan as a table of stages, followed by the
+// steps of each stage. Wait, read, compute and write times are shown as the
+// average and maximum ratios relative to the longest time spent by any shard
+// in any stage. The slot time of each stage (which the API reports as slotMs)
+// isn't exposed by the BigQuery client library, so only the job's total slot
+// time is shown.
+func (s *JobStats) PlanText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Job: %s\n", s.JobID)
+	fmt.Fprintf(&b, "Statement type: %s\n", s.StatementType)
+	fmt.Fprintf(&b, "Bytes processed: %d (billed: %d)\n", s.TotalBytesProcessed, s.TotalBytesBilled)
+	fmt.Fprintf(&b, "Slot time: %s\n", time.Duration(s.SlotMillis)*time.Millisecond)
+	fmt.Fprintf(&b, "Cache hit: %t\n", s.CacheHit)
+	if len(s.QueryPlan) == 0 {
+		b.WriteString("\nNo query plan available.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tStage\tStatus\tInputs\tRecords read\tRecords written\tWait\tRead\tCompute\tWrite\tShuffle bytes\tSpilled bytes")
+	for _, stage := range s.QueryPlan {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			stage.ID,
+			stage.Name,
+			stage.Status,
+			formatStageIDs(stage.InputStages),
+			stage.RecordsRead,
+			stage.RecordsWritten,
+			formatRatios(stage.WaitRatioAvg, stage.WaitRatioMax),
+			formatRatios(stage.ReadRatioAvg, stage.ReadRatioMax),
+			formatRatios(stage.ComputeRatioAvg, stage.ComputeRatioMax),
+			formatRatios(stage.WriteRatioAvg, stage.WriteRatioMax),
+			stage.ShuffleOutputBytes,
+			stage.ShuffleOutputBytesSpilled,
+		)
+	}
+	w.Flush()
+
+	for _, stage := range s.QueryPlan {
+		fmt.Fprintf(&b, "\n%d: %s\n", stage.ID, stage.Name)
+		for _, step := range stage.Steps {
+			fmt.Fprintf(&b, "  %s\n", step.Kind)
+			for _, substep := range step.Substeps {
+				fmt.Fprintf(&b, "    %s\n", substep)
+			}
+		}
+	}
+	return b.String()
+}
+
+// PlanDOT renders the job's query plan as a graph in the DOT language (e.g.
+// for rendering with Graphviz), with an edge from each stage to the stages
+// that consume its output.
+func (s *JobStats) PlanDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph plan {\n")
+	b.WriteString("  node [shape=box, fontname=monospace];\n")
+	for _, stage := range s.QueryPlan {
+		label := fmt.Sprintf(
+			"%s\n%s\nrecords: %d read, %d written\nwait %s, read %s, compute %s, write %s\nshuffle: %d bytes (%d spilled)",
+			stage.Name,
+			stage.Status,
+			stage.RecordsRead,
+			stage.RecordsWritten,
+			formatRatios(stage.WaitRatioAvg, stage.WaitRatioMax),
+			formatRatios(stage.ReadRatioAvg, stage.ReadRatioMax),
+			formatRatios(stage.ComputeRatioAvg, stage.ComputeRatioMax),
+			formatRatios(stage.WriteRatioAvg, stage.WriteRatioMax),
+			stage.ShuffleOutputBytes,
+			stage.ShuffleOutputBytesSpilled,
+		)
+		fmt.Fprintf(&b, "  stage%d [label=%s];\n", stage.ID, dotQuote(label))
+	}
+	for _, stage := range s.QueryPlan {
+		for _, input := range stage.InputStages {
+			fmt.Fprintf(&b, "  stage%d -> stage%d;\n", input, stage.ID)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// TimelineText renders the job's execution timeline as a table.
+func (s *JobStats) TimelineText() string {
+	if len(s.Timeline) == 0 {
+		return "No timeline available.\n"
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Elapsed\tActive units\tCompleted units\tPending units\tSlot time")
+	for _, sample := range s.Timeline {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n",
+			sample.Elapsed,
+			sample.ActiveUnits,
+			sample.CompletedUnits,
+			sample.PendingUnits,
+			time.Duration(sample.SlotMillis)*time.Millisecond,
+		)
+	}
+	w.Flush()
+	return b.String()
+}
+
+func formatStageIDs(ids []int64) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(strs, ",")
+}
+
+// Quotes the string as a DOT string literal, in which newlines start a new
+// (centered) line of a label.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// Formats the average and maximum ratios, e.g. "0.12/0.50".
+func formatRatios(avgRatio, maxRatio float64) string {
+	return fmt.Sprintf("%.2f/%.2f", avgRatio, maxRatio)
+}
@@ -0,0 +1,278 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+// An OUT or INOUT parameter passed to a stored procedure via [sql.Out].
+type outParam struct {
+	name     string
+	variable string
+	out      sql.Out
+}
+
+// Splits the [sql.Out] arguments from the regular query arguments. INOUT
+// parameters are also returned as regular arguments, so that their initial
+// value can be passed to the script that calls the procedure.
+func extractOutParams(args []driver.NamedValue) ([]outParam, []driver.NamedValue, error) {
+	var outs []outParam
+	var rest []driver.NamedValue
+	for _, arg := range args {
+		out, ok := arg.Value.(sql.Out)
+		if !ok {
+			rest = append(rest, arg)
+			continue
+		}
+
+		if arg.Name == "" {
+			return nil, nil, fmt.Errorf("OUT parameter at position %d must be named (use sql.Named)", arg.Ordinal)
+		}
+		if reflect.ValueOf(out.Dest).Kind() != reflect.Pointer {
+			return nil, nil, fmt.Errorf("destination for OUT parameter %s must be a pointer", arg.Name)
+		}
+
+		outs = append(outs, outParam{
+			name:     arg.Name,
+			variable: "_out_" + arg.Name,
+			out:      out,
+		})
+
+		if out.In {
+			value, err := outInValue(out)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid value for INOUT parameter %s: %w", arg.Name, err)
+			}
+			rest = append(rest, driver.NamedValue{
+				Name:    arg.Name,
+				Ordinal: arg.Ordinal,
+				Value:   value,
+			})
+		}
+	}
+	return outs, rest, nil
+}
+
+// Returns the value currently pointed to by the destination of an INOUT
+// parameter.
+func outInValue(out sql.Out) (any, error) {
+	value := reflect.ValueOf(out.Dest).Elem().Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return value, nil
+}
+
+// Wraps the CALL statement in a script that declares a variable for each OUT
+// or INOUT parameter, passes the variables to the procedure in place of the
+// corresponding query parameters, and finally selects the variables so that
+// their values can be read back. The script is a BEGIN ... END block, so that
+// the variables don't outlive it in the session.
+func buildCallScript(query string, outs []outParam) (string, error) {
+	var b strings.Builder
+	b.WriteString("BEGIN\n")
+	selects := make([]string, len(outs))
+	for i, o := range outs {
+		if o.out.In {
+			fmt.Fprintf(&b, "DECLARE %s DEFAULT @%s;\n", o.variable, o.name)
+		} else {
+			typ, err := outParamType(o.out.Dest)
+			if err != nil {
+				return "", fmt.Errorf("OUT parameter %s: %w", o.name, err)
+			}
+			fmt.Fprintf(&b, "DECLARE %s %s;\n", o.variable, typ)
+		}
+
+		referenced := false
+		query = rewriteParams(query, func(ref paramRef) string {
+			if !strings.EqualFold(ref.name, o.name) {
+				return ref.text
+			}
+			referenced = true
+			return o.variable
+		})
+		if !referenced {
+			return "", fmt.Errorf("OUT parameter %s is not referenced by the query", o.name)
+		}
+		selects[i] = fmt.Sprintf("%s AS `%s`", o.variable, o.name)
+	}
+
+	b.WriteString(strings.TrimRight(strings.TrimSpace(query), ";"))
+	b.WriteString(";\n")
+	fmt.Fprintf(&b, "SELECT %s;\nEND;", strings.Join(selects, ", "))
+	return b.String(), nil
+}
+
+var (
+	bigRatType        = reflect.TypeFor[big.Rat]()
+	civilDateType     = reflect.TypeFor[civil.Date]()
+	civilTimeType     = reflect.TypeFor[civil.Time]()
+	civilDateTimeType = reflect.TypeFor[civil.DateTime]()
+	timeType          = reflect.TypeFor[time.Time]()
+	nullStringType    = reflect.TypeFor[sql.NullString]()
+	nullInt64Type     = reflect.TypeFor[sql.NullInt64]()
+	nullInt32Type     = reflect.TypeFor[sql.NullInt32]()
+	nullInt16Type     = reflect.TypeFor[sql.NullInt16]()
+	nullFloat64Type   = reflect.TypeFor[sql.NullFloat64]()
+	nullBoolType      = reflect.TypeFor[sql.NullBool]()
+	nullTimeType      = reflect.TypeFor[sql.NullTime]()
+)
+
+// Infers the BigQuery type of an OUT parameter from the Go type of its
+// destination, since BigQuery variables must be declared with a type.
+func outParamType(dest any) (string, error) {
+	t := reflect.TypeOf(dest).Elem()
+	switch t {
+	case bigRatType:
+		return "NUMERIC", nil
+	case civilDateType:
+		return "DATE", nil
+	case civilTimeType:
+		return "TIME", nil
+	case civilDateTimeType:
+		return "DATETIME", nil
+	case timeType, nullTimeType:
+		return "TIMESTAMP", nil
+	case nullStringType:
+		return "STRING", nil
+	case nullInt64Type, nullInt32Type, nullInt16Type:
+		return "INT64", nil
+	case nullFloat64Type:
+		return "FLOAT64", nil
+	case nullBoolType:
+		return "BOOL", nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "STRING", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "INT64", nil
+	case reflect.Float32, reflect.Float64:
+		return "FLOAT64", nil
+	case reflect.Bool:
+		return "BOOL", nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "BYTES", nil
+		}
+	}
+	return "", fmt.Errorf("cannot infer BigQuery type from destination type %s (use an INOUT parameter instead)", t)
+}
+
+// Executes a CALL statement with OUT/INOUT parameters, and writes the final
+// values of the parameters to their destinations.
+func (s *stmt) execCall(ctx context.Context, opts options, outs []outParam, args []driver.NamedValue) (*bigquery.Job, *bigquery.RowIterator, error) {
+	// BigQuery doesn't allow destination tables for scripts.
+	if opts.destinationOrContext(ctx) != nil {
+		return nil, nil, errors.New("destination tables are not supported with OUT parameters")
+	}
+
+	script, err := buildCallScript(s.query, outs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	call := &stmt{
+		conn:  s.conn,
+		query: script,
+	}
+	job, iterator, err := call.iterator(ctx, opts, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Dry runs don't run the procedure, so there are no values to read back.
+	if iterator == nil {
+		return nil, nil, errors.New("OUT parameters are not supported for dry runs")
+	}
+
+	dests := make([]any, len(outs))
+	for i, o := range outs {
+		dests[i] = outParamDest(o.out.Dest)
+	}
+	r := &rows{
+		job:        job,
+		iterator:   iterator,
+		conversion: newConversion(s.conn.config),
+	}
+	if err := scanRow(ctx, r, dests); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errors.New("procedure call returned no OUT parameter values")
+		}
+		return nil, nil, err
+	}
+	return job, iterator, nil
+}
+
+// Returns the destination to scan the value of an OUT parameter into, which is
+// dest itself unless database/sql can't convert values to its type (see
+// fieldScanner).
+func outParamDest(dest any) any {
+	t := reflect.TypeOf(dest).Elem()
+	if needsFieldScanner(t) {
+		return &fieldScanner{
+			dest: reflect.ValueOf(dest).Elem(),
+			json: !implementsTextUnmarshaler(t),
+		}
+	}
+	return dest
+}
+
+// Scans the first row of driver rows into dests, with the same conversions as
+// [sql.Rows.Scan] (by serving the rows from a throwaway database).
+func scanRow(ctx context.Context, r driver.Rows, dests []any) error {
+	db := sql.OpenDB(rowsConnector{r})
+	defer db.Close()
+	return db.QueryRowContext(ctx, "").Scan(dests...)
+}
+
+// A connector whose connections return the given rows for any query.
+type rowsConnector struct {
+	rows driver.Rows
+}
+
+var (
+	_ driver.Connector      = rowsConnector{}
+	_ driver.Conn           = rowsConn{}
+	_ driver.QueryerContext = rowsConn{}
+)
+
+func (c rowsConnector) Connect(context.Context) (driver.Conn, error) {
+	return rowsConn(c), nil
+}
+
+func (c rowsConnector) Driver() driver.Driver {
+	return &bigQueryDriver{}
+}
+
+type rowsConn struct {
+	rows driver.Rows
+}
+
+func (c rowsConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return c.rows, nil
+}
+
+func (c rowsConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c rowsConn) Close() error {
+	return nil
+}
+
+func (c rowsConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
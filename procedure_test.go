@@ -0,0 +1,91 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestOutParams(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{
+				{Name: "count", Type: "INTEGER"},
+				{Name: "total", Type: "NUMERIC"},
+				{Name: "day", Type: "DATE"},
+				{Name: "name", Type: "STRING"},
+				{Name: "note", Type: "STRING"},
+			},
+			Rows: [][]any{{"42", "12.5", "2024-03-01", "Ada", nil}},
+		}
+	})
+	db := server.open(server.config())
+
+	var (
+		count int32
+		total big.Rat
+		day   civil.Date
+		name  = "initial"
+		note  sql.NullString
+	)
+	if _, err := db.ExecContext(context.Background(),
+		"CALL dataset.summarize(@count, @total, @day, @name, @note);",
+		sql.Named("count", sql.Out{Dest: &count}),
+		sql.Named("total", sql.Out{Dest: &total}),
+		sql.Named("day", sql.Out{Dest: &day}),
+		sql.Named("name", sql.Out{Dest: &name, In: true}),
+		sql.Named("note", sql.Out{Dest: &note}),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 42 || total.Cmp(big.NewRat(25, 2)) != 0 || day != (civil.Date{Year: 2024, Month: 3, Day: 1}) ||
+		name != "Ada" || note.Valid {
+		t.Errorf("got count=%d total=%s day=%s name=%q note=%v", count, total.RatString(), day, name, note)
+	}
+
+	queries := server.takeQueries()
+	if len(queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(queries))
+	}
+	if q := queries[0]; !strings.HasPrefix(q.SQL, "BEGIN\n") || q.Params["name"] != "initial" {
+		t.Errorf("unexpected call script %q with params %v", q.SQL, q.Params)
+	}
+}
+
+func TestOutParamsErrors(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "count", Type: "STRING"}},
+			Rows:   [][]any{{"many"}},
+		}
+	})
+	ctx := context.Background()
+	var count int
+	out := sql.Named("count", sql.Out{Dest: &count})
+
+	db := server.open(server.config())
+	if _, err := db.ExecContext(ctx, "CALL dataset.count(@count);", out); err == nil {
+		t.Error("scanning a non-numeric string into an int: want an error")
+	}
+
+	dryRun := GetQuery(func(q *bigquery.Query) { q.DryRun = true })
+	if _, err := db.ExecContext(ctx, "CALL dataset.count(@count);", out, dryRun); err == nil ||
+		!strings.Contains(err.Error(), "dry run") {
+		t.Errorf("dry run via GetQuery: got %v, want a dry run error", err)
+	}
+
+	config := server.config()
+	config.DryRun = true
+	db = server.open(config)
+	if _, err := db.ExecContext(ctx, "CALL dataset.count(@count);", out); err == nil ||
+		!strings.Contains(err.Error(), "dry run") {
+		t.Errorf("dry run via Config.DryRun: got %v, want a dry run error", err)
+	}
+}
@@ -0,0 +1,226 @@
+package bigquery
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+// RangeElement is the set of Go types corresponding to the element types
+// supported by the BigQuery RANGE type (DATE, DATETIME and TIMESTAMP).
+type RangeElement interface {
+	civil.Date | civil.DateTime | time.Time
+}
+
+// Range is a nullable RANGE value, which can be used to scan RANGE columns and
+// to pass RANGE query parameters. A nil Start or End represents an unbounded
+// start or end, respectively. As in BigQuery, the start is inclusive and the
+// end is exclusive.
+type Range[T RangeElement] struct {
+	Start *T
+	End   *T
+	Valid bool
+}
+
+// The format used for TIMESTAMP range elements, which is a valid BigQuery
+// timestamp literal.
+const rangeTimestampLayout = "2006-01-02 15:04:05.999999-07:00"
+
+// The formats accepted for TIMESTAMP range elements, which include BigQuery's
+// canonical format (e.g. "2024-01-01 00:00:00+00"), with or without
+// fractional seconds (which are accepted by all layouts when parsing).
+var rangeTimestampLayouts = []string{
+	rangeTimestampLayout,
+	"2006-01-02 15:04:05-07",
+	time.RFC3339Nano,
+}
+
+const unboundedRange = "UNBOUNDED"
+
+func (r *Range[T]) Scan(src any) error {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		*r = Range[T]{}
+		return nil
+	case string:
+		s = src
+	case []byte:
+		s = string(src)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, r)
+	}
+
+	startStr, endStr, err := splitRange(s)
+	if err != nil {
+		return err
+	}
+	start, err := parseRangeElement[T](startStr)
+	if err != nil {
+		return err
+	}
+	end, err := parseRangeElement[T](endStr)
+	if err != nil {
+		return err
+	}
+
+	*r = Range[T]{Start: start, End: end, Valid: true}
+	return nil
+}
+
+// Value returns the canonical string representation of the range (e.g.
+// "[2024-01-01, UNBOUNDED)").
+func (r Range[T]) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+	return r.String(), nil
+}
+
+func (r Range[T]) String() string {
+	return formatRange(rangeElementValue(r.Start), rangeElementValue(r.End))
+}
+
+func rangeElementValue[T RangeElement](elem *T) bigquery.Value {
+	if elem == nil {
+		return nil
+	}
+	return *elem
+}
+
+// Returns the range as a typed query parameter value, so that the element type
+// is known even when both the start and end are unbounded (or the range is
+// NULL).
+func (r Range[T]) queryParameterValue() *bigquery.QueryParameterValue {
+	typ := bigquery.StandardSQLDataType{
+		TypeKind:         "RANGE",
+		RangeElementType: &bigquery.StandardSQLDataType{TypeKind: rangeElementTypeKind[T]()},
+	}
+	if !r.Valid {
+		return &bigquery.QueryParameterValue{Type: typ, Value: bigquery.NullString{}}
+	}
+	return &bigquery.QueryParameterValue{
+		Type: typ,
+		Value: &bigquery.RangeValue{
+			Start: rangeElementValue(r.Start),
+			End:   rangeElementValue(r.End),
+		},
+	}
+}
+
+type rangeParam interface {
+	queryParameterValue() *bigquery.QueryParameterValue
+}
+
+var (
+	_ rangeParam = Range[civil.Date]{}
+	_ rangeParam = Range[civil.DateTime]{}
+	_ rangeParam = Range[time.Time]{}
+)
+
+func checkRangeValue(named *driver.NamedValue) bool {
+	switch value := named.Value.(type) {
+	case rangeParam:
+		// Nil *Range values are handled by the default converter (as NULL).
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			return false
+		}
+		named.Value = value.queryParameterValue()
+		return true
+	case *bigquery.RangeValue, *bigquery.QueryParameterValue:
+		// Passed through to the BigQuery client as-is.
+		return true
+	}
+	return false
+}
+
+func rangeElementTypeKind[T RangeElement]() string {
+	var zero T
+	switch any(zero).(type) {
+	case civil.Date:
+		return "DATE"
+	case civil.DateTime:
+		return "DATETIME"
+	default:
+		return "TIMESTAMP"
+	}
+}
+
+// Splits a range of the form "[start, end)" into its start and end.
+func splitRange(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, ")") {
+		return "", "", fmt.Errorf("invalid range: %q", s)
+	}
+	start, end, ok := strings.Cut(s[1:len(s)-1], ",")
+	if !ok {
+		return "", "", fmt.Errorf("invalid range: %q", s)
+	}
+	return strings.TrimSpace(start), strings.TrimSpace(end), nil
+}
+
+func parseRangeElement[T RangeElement](s string) (*T, error) {
+	if strings.EqualFold(s, unboundedRange) || strings.EqualFold(s, "NULL") {
+		return nil, nil
+	}
+
+	var elem T
+	var err error
+	switch e := any(&elem).(type) {
+	case *civil.Date:
+		*e, err = civil.ParseDate(s)
+	case *civil.DateTime:
+		*e, err = civil.ParseDateTime(strings.Replace(s, " ", "T", 1))
+	case *time.Time:
+		for _, layout := range rangeTimestampLayouts {
+			if *e, err = time.Parse(layout, s); err == nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid range element: %q", s)
+	}
+	return &elem, nil
+}
+
+func formatRange(start, end bigquery.Value) string {
+	return fmt.Sprintf("[%s, %s)", formatRangeElement(start), formatRangeElement(end))
+}
+
+func formatRangeElement(elem bigquery.Value) string {
+	switch elem := elem.(type) {
+	case nil:
+		return unboundedRange
+	case time.Time:
+		return elem.UTC().Format(rangeTimestampLayout)
+	case fmt.Stringer:
+		return elem.String()
+	default:
+		return fmt.Sprint(elem)
+	}
+}
+
+func convertRangeType(field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
+	switch val := value.(type) {
+	case nil:
+		return nil, nil
+	case *bigquery.RangeValue:
+		if val == nil {
+			return nil, nil
+		}
+		return formatRange(val.Start, val.End), nil
+	case string:
+		return val, nil
+	default:
+		return nil, &unexpectedTypeError{
+			FieldType: field.Type,
+			Expected:  reflect.TypeFor[*bigquery.RangeValue](),
+			Actual:    val,
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package bigquery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRangeTimestampScan(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 12, 30, 0, 0, time.UTC)
+	fractional := time.Date(2024, 1, 1, 0, 0, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		src   string
+		start *time.Time
+		end   *time.Time
+	}{
+		{"[2024-01-01 00:00:00+00, 2024-01-02 12:30:00+00)", &start, &end},
+		{"[2024-01-01 00:00:00.123456+00, UNBOUNDED)", &fractional, nil},
+		{"[2024-01-01 02:00:00+02, 2024-01-02 12:30:00+00:00)", &start, &end},
+		{"[UNBOUNDED, 2024-01-02T12:30:00Z)", nil, &end},
+		{"[2024-01-01 00:00:00.123456-00:00, NULL)", &fractional, nil},
+	}
+	for _, test := range tests {
+		var r Range[time.Time]
+		if err := r.Scan(test.src); err != nil {
+			t.Errorf("Scan(%q): %v", test.src, err)
+			continue
+		}
+		if !r.Valid || !equalTime(r.Start, test.start) || !equalTime(r.End, test.end) {
+			t.Errorf("Scan(%q) = [%v, %v), want [%v, %v)", test.src, r.Start, r.End, test.start, test.end)
+		}
+	}
+}
+
+func TestRangeTimestampScanInvalid(t *testing.T) {
+	var r Range[time.Time]
+	if err := r.Scan("[2024-01-01, UNBOUNDED)"); err == nil {
+		t.Errorf("Scan succeeded for a DATE element")
+	}
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -12,7 +12,10 @@ var (
 )
 
 type result struct {
+	job      *bigquery.Job
 	iterator *bigquery.RowIterator
+	// The destination table the results of the query were written to, if any.
+	destination *Destination
 }
 
 func (r *result) LastInsertId() (int64, error) {
@@ -20,5 +23,26 @@ func (r *result) LastInsertId() (int64, error) {
 }
 
 func (r *result) RowsAffected() (int64, error) {
-	return int64(r.iterator.TotalRows), nil
+	// Dry runs don't affect any rows.
+	if r.iterator == nil {
+		return 0, nil
+	}
+	details := queryStatistics(r.job)
+	if details == nil || details.StatementType != "SELECT" {
+		return int64(r.iterator.TotalRows), nil
+	}
+
+	// SELECT statements only affect rows when writing to a destination table.
+	// When appending to it, the results include the existing rows, so the
+	// rows written are read from the query plan instead.
+	if r.destination == nil {
+		return 0, errors.New("RowsAffected is not supported for SELECT statements without a destination table")
+	}
+	if r.destination.WriteDisposition != bigquery.WriteAppend {
+		return int64(r.iterator.TotalRows), nil
+	}
+	if n, ok := rowsWritten(r.job); ok {
+		return n, nil
+	}
+	return 0, errors.New("the number of rows appended to the destination table is not available")
 }
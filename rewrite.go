@@ -0,0 +1,137 @@
+package bigquery
+
+import (
+	"strings"
+)
+
+// A reference to a query parameter within the query text, either by name
+// (@name) or by position (?).
+type paramRef struct {
+	// Name of the parameter (without the leading @), or the empty string for
+	// positional parameters.
+	name string
+	// Ordinal position (starting at 1) of positional parameters, or zero for
+	// named parameters.
+	ordinal int
+	// The original text of the reference.
+	text string
+}
+
+// Calls replace for every query parameter reference in the query, and
+// substitutes the reference with the returned text. References within string
+// literals, quoted identifiers and comments are ignored, as are references to
+// system variables (@@name).
+func rewriteParams(query string, replace func(ref paramRef) string) string {
+	var b strings.Builder
+	ordinal := 0
+	for i := 0; i < len(query); {
+		end := skipLiteral(query, i)
+		if end > i {
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		switch c := query[i]; {
+		case c == '?':
+			ordinal++
+			b.WriteString(replace(paramRef{ordinal: ordinal, text: "?"}))
+			i++
+		case c == '@' && strings.HasPrefix(query[i:], "@@"):
+			end := i + 2
+			for end < len(query) && isIdentChar(query[end]) {
+				end++
+			}
+			b.WriteString(query[i:end])
+			i = end
+		case c == '@':
+			end := i + 1
+			for end < len(query) && isIdentChar(query[end]) {
+				end++
+			}
+			if end == i+1 {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			b.WriteString(replace(paramRef{name: query[i+1 : end], text: query[i:end]}))
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// Splits a query into its statements, at the semicolons outside of string
+// literals, quoted identifiers and comments. Comments are replaced by spaces.
+func splitStatements(query string) []string {
+	var statements []string
+	var b strings.Builder
+	for i := 0; i < len(query); {
+		if end := skipLiteral(query, i); end > i {
+			switch query[i] {
+			case '-', '#', '/':
+				b.WriteByte(' ')
+			default:
+				b.WriteString(query[i:end])
+			}
+			i = end
+			continue
+		}
+		if query[i] == ';' {
+			statements = append(statements, b.String())
+			b.Reset()
+		} else {
+			b.WriteByte(query[i])
+		}
+		i++
+	}
+	if strings.TrimSpace(b.String()) != "" {
+		statements = append(statements, b.String())
+	}
+	return statements
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// If a string literal, quoted identifier or comment starts at position i of
+// the query, returns the position just after its end. Otherwise, returns i.
+func skipLiteral(query string, i int) int {
+	rest := query[i:]
+	switch {
+	case strings.HasPrefix(rest, "--"), rest[0] == '#':
+		if end := strings.IndexByte(rest, '\n'); end >= 0 {
+			return i + end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(rest, "/*"):
+		if end := strings.Index(rest[2:], "*/"); end >= 0 {
+			return i + 2 + end + 2
+		}
+		return len(query)
+	case strings.HasPrefix(rest, `'''`), strings.HasPrefix(rest, `"""`):
+		return skipQuoted(query, i+3, rest[:3])
+	case rest[0] == '\'', rest[0] == '"', rest[0] == '`':
+		return skipQuoted(query, i+1, rest[:1])
+	default:
+		return i
+	}
+}
+
+func skipQuoted(query string, i int, quote string) int {
+	for i < len(query) {
+		switch {
+		case query[i] == '\\':
+			i += 2
+		case strings.HasPrefix(query[i:], quote):
+			return i + len(quote)
+		default:
+			i++
+		}
+	}
+	return len(query)
+}
@@ -24,7 +24,9 @@ var (
 )
 
 type rows struct {
+	job        *bigquery.Job
 	iterator   *bigquery.RowIterator
+	conversion conversion
 	nextCalled bool
 	prevValues []bigquery.Value
 	prevErr    error
@@ -111,6 +113,9 @@ var (
 
 func (r *rows) ColumnTypeScanType(index int) reflect.Type {
 	field := r.schema()[index]
+	if scanType, ok := r.conversion.scanType(field); ok {
+		return scanType
+	}
 
 	switch field.Type {
 	case bigquery.IntegerFieldType:
@@ -152,7 +157,7 @@ func (r *rows) Next(dest []driver.Value) error {
 
 	schema := r.schema()
 	for idx := range dest {
-		value, err := convertValue(schema[idx], values[idx])
+		value, err := convertValue(r.conversion, schema[idx], values[idx])
 		if err != nil {
 			return err
 		}
@@ -162,8 +167,10 @@ func (r *rows) Next(dest []driver.Value) error {
 }
 
 func (r *rows) schema() bigquery.Schema {
+	// Dry runs don't return any rows, but their statistics include the schema
+	// the results would have.
 	if r.iterator == nil {
-		return nil
+		return dryRunSchema(r.job)
 	}
 
 	// Must call next before we can access the schema.
@@ -200,8 +207,32 @@ func (r *rows) next() ([]bigquery.Value, error) {
 	return values, nil
 }
 
-func convertValue(field *bigquery.FieldSchema, value bigquery.Value) (driver.Value, error) {
-	val, err := convertValueHelper(field, value)
+// Settings that control how BigQuery values are converted to driver.Value
+// values (derived from the connector's Config).
+type conversion struct {
+	typeMapping     TypeMapping
+	timeZone        *time.Location
+	converters      map[bigquery.FieldType]Converter
+	geographyFormat GeographyFormat
+	jsonRawMessage  bool
+}
+
+func newConversion(config Config) conversion {
+	timeZone := config.TimeZone
+	if timeZone == nil {
+		timeZone = time.UTC
+	}
+	return conversion{
+		typeMapping:     config.TypeMapping,
+		timeZone:        timeZone,
+		converters:      config.Converters,
+		geographyFormat: config.GeographyFormat,
+		jsonRawMessage:  config.JSONRawMessage,
+	}
+}
+
+func convertValue(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (driver.Value, error) {
+	val, err := convertValueHelper(conv, field, value)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +241,10 @@ func convertValue(field *bigquery.FieldSchema, value bigquery.Value) (driver.Val
 		return val, nil
 	}
 
+	if raw, ok := val.(json.RawMessage); ok {
+		return []byte(raw), nil
+	}
+
 	// Marshal ARRAY and RECORD types to JSON, since arrays/maps aren't
 	// valid driver.Value types.
 	out, err := json.Marshal(val)
@@ -219,14 +254,26 @@ func convertValue(field *bigquery.FieldSchema, value bigquery.Value) (driver.Val
 	return out, nil
 }
 
-func convertValueHelper(field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
+func convertValueHelper(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
 	if field.Repeated {
-		return convertRepeatedType(field, value)
+		return convertRepeatedType(conv, field, value)
 	}
-	return convertUnitType(field, value)
+	return convertUnitType(conv, field, value)
 }
 
-func convertUnitType(field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
+func convertUnitType(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
+	if val, ok, err := conv.convertCustom(field, value); ok {
+		return val, err
+	}
+
+	val, err := convertUnitTypeHelper(conv, field, value)
+	if err != nil {
+		return conv.convertLenient(value, err)
+	}
+	return val, nil
+}
+
+func convertUnitTypeHelper(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (any, error) {
 	switch field.Type {
 	case bigquery.StringFieldType:
 		return convertBasicType[string](field, value)
@@ -251,15 +298,15 @@ func convertUnitType(field *bigquery.FieldSchema, value bigquery.Value) (any, er
 	case bigquery.BigNumericFieldType:
 		return convertRationalType(field, value, bigquery.BigNumericString)
 	case bigquery.GeographyFieldType:
-		return convertBasicType[string](field, value)
+		return convertGeographyType(field, value, conv.geographyFormat)
 	case bigquery.IntervalFieldType:
 		return convertStringerType[*bigquery.IntervalValue](field, value)
 	case bigquery.RangeFieldType:
-		return convertBasicType[string](field, value)
+		return convertRangeType(field, value)
 	case bigquery.JSONFieldType:
-		return convertBytesType[string](field, value)
+		return convertJSONType(conv, field, value)
 	case bigquery.RecordFieldType:
-		return convertRecordType(field, value)
+		return convertRecordType(conv, field, value)
 	default:
 		return nil, &invalidFieldTypeError{
 			FieldType: field.Type,
@@ -267,14 +314,14 @@ func convertUnitType(field *bigquery.FieldSchema, value bigquery.Value) (any, er
 	}
 }
 
-func convertRepeatedType(field *bigquery.FieldSchema, value bigquery.Value) ([]any, error) {
+func convertRepeatedType(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) ([]any, error) {
 	switch val := value.(type) {
 	case nil:
 		return nil, nil
 	case []bigquery.Value:
 		a := make([]any, len(val))
 		for i, v := range val {
-			av, err := convertUnitType(field, v)
+			av, err := convertUnitType(conv, field, v)
 			if err != nil {
 				return nil, err
 			}
@@ -290,14 +337,14 @@ func convertRepeatedType(field *bigquery.FieldSchema, value bigquery.Value) ([]a
 	}
 }
 
-func convertRecordType(field *bigquery.FieldSchema, value bigquery.Value) (map[string]any, error) {
+func convertRecordType(conv conversion, field *bigquery.FieldSchema, value bigquery.Value) (map[string]any, error) {
 	switch val := value.(type) {
 	case nil:
 		return nil, nil
 	case []bigquery.Value:
 		m := map[string]any{}
 		for i, mf := range field.Schema {
-			mv, err := convertValueHelper(mf, val[i])
+			mv, err := convertValueHelper(conv, mf, val[i])
 			if err != nil {
 				return nil, err
 			}
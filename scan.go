@@ -0,0 +1,356 @@
+package bigquery
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanStruct scans the current row of rows (i.e. after a call to
+// [sql.Rows.Next]) into a new value of type T, which must be a struct (or a
+// pointer to a struct).
+//
+// Columns are mapped to struct fields via `bigquery:"name"` tags, falling back
+// to (case-insensitive) field names. Fields tagged with `bigquery:"-"` are
+// ignored, and the fields of embedded structs are treated as fields of the
+// outer struct. An error listing the unmapped columns is returned if any
+// column has no corresponding field.
+//
+// STRUCT, ARRAY and JSON columns are decoded from their JSON representation
+// into (nested) struct, slice, map and interface fields, with the fields of
+// nested structs mapped in the same way as columns. Fields whose types
+// implement [encoding.TextUnmarshaler] (e.g. civil.Date or big.Rat) are parsed
+// from string values. All other fields are scanned as by [sql.Rows.Scan],
+// except that NULL values leave their zero value.
+func ScanStruct[T any](rows *sql.Rows) (T, error) {
+	var out T
+	scanner, err := newStructScanner(rows, reflect.TypeFor[T]())
+	if err != nil {
+		return out, err
+	}
+	err = scanner.scan(rows, reflect.ValueOf(&out).Elem())
+	return out, err
+}
+
+// CollectRows scans all remaining rows into values of type T (as described
+// for [ScanStruct]), and closes rows.
+func CollectRows[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+
+	scanner, err := newStructScanner(rows, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+	for rows.Next() {
+		var value T
+		if err := scanner.scan(rows, reflect.ValueOf(&value).Elem()); err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, rows.Close()
+}
+
+type structScanner struct {
+	// Index paths of the struct fields corresponding to each column.
+	fields [][]int
+	// Whether each column holds JSON (i.e. is a STRUCT, ARRAY or JSON column).
+	jsonColumns []bool
+}
+
+func newStructScanner(sqlRows *sql.Rows, t reflect.Type) (*structScanner, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot scan into %s (must be a struct)", t)
+	}
+
+	columnTypes, err := sqlRows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	fieldMap := structFieldMap(t)
+	fields := make([][]int, len(columnTypes))
+	jsonColumns := make([]bool, len(columnTypes))
+	var unmapped []string
+	for i, columnType := range columnTypes {
+		index, ok := fieldMap.lookup(columnType.Name())
+		if !ok {
+			unmapped = append(unmapped, columnType.Name())
+			continue
+		}
+		fields[i] = index
+
+		typeName := columnType.DatabaseTypeName()
+		jsonColumns[i] = typeName == "JSON" ||
+			strings.HasPrefix(typeName, "STRUCT<") ||
+			strings.HasPrefix(typeName, "ARRAY<")
+	}
+	if len(unmapped) > 0 {
+		return nil, fmt.Errorf("no fields in %s for columns: %s", t, strings.Join(unmapped, ", "))
+	}
+
+	return &structScanner{
+		fields:      fields,
+		jsonColumns: jsonColumns,
+	}, nil
+}
+
+func (s *structScanner) scan(sqlRows *sql.Rows, dest reflect.Value) error {
+	if dest.Kind() == reflect.Pointer {
+		dest.Set(reflect.New(dest.Type().Elem()))
+		dest = dest.Elem()
+	}
+
+	targets := make([]any, len(s.fields))
+	var nullable []nullableField
+	for i, index := range s.fields {
+		field := dest.FieldByIndex(index)
+		switch {
+		case field.Addr().Type().Implements(scannerType):
+			targets[i] = field.Addr().Interface()
+		case s.jsonColumns[i] && decodesJSON(field.Type()):
+			targets[i] = &fieldScanner{dest: field, json: true}
+		case needsFieldScanner(field.Type()):
+			targets[i] = &fieldScanner{dest: field, json: !implementsTextUnmarshaler(field.Type())}
+		case field.Kind() == reflect.Pointer:
+			targets[i] = field.Addr().Interface()
+		default:
+			// Scan via a pointer, so that NULL values leave the field's
+			// zero value rather than failing.
+			ptr := reflect.New(reflect.PointerTo(field.Type()))
+			nullable = append(nullable, nullableField{ptr: ptr, dest: field})
+			targets[i] = ptr.Interface()
+		}
+	}
+	if err := sqlRows.Scan(targets...); err != nil {
+		return err
+	}
+	for _, f := range nullable {
+		if ptr := f.ptr.Elem(); !ptr.IsNil() {
+			f.dest.Set(ptr.Elem())
+		}
+	}
+	return nil
+}
+
+// A struct field scanned via a pointer to a pointer to its type.
+type nullableField struct {
+	ptr  reflect.Value
+	dest reflect.Value
+}
+
+var (
+	scannerType         = reflect.TypeFor[sql.Scanner]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Returns the type pointed to by t, if t is a pointer type.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+func implementsTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(baseType(t)).Implements(textUnmarshalerType)
+}
+
+// Reports whether values of JSON columns are decoded into fields of type t
+// (rather than being scanned as bytes or strings).
+func decodesJSON(t reflect.Type) bool {
+	t = baseType(t)
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array, reflect.Interface:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// Reports whether fields of type t can't be scanned into by database/sql
+// (regardless of the column type).
+func needsFieldScanner(t reflect.Type) bool {
+	if reflect.PointerTo(baseType(t)).Implements(scannerType) {
+		return false
+	}
+	return implementsTextUnmarshaler(t) || (decodesJSON(t) && baseType(t).Kind() != reflect.Interface)
+}
+
+// A scan destination for struct fields database/sql can't convert values to.
+// Values are either decoded from JSON (for STRUCT and ARRAY values, which this
+// driver returns as JSON), or parsed via encoding.TextUnmarshaler (for e.g.
+// civil.Date and big.Rat fields, as this driver returns DATE and NUMERIC
+// values as strings).
+type fieldScanner struct {
+	dest reflect.Value
+	json bool
+}
+
+func (s *fieldScanner) Scan(src any) error {
+	dest := s.dest
+	if src == nil {
+		dest.SetZero()
+		return nil
+	}
+	for dest.Kind() == reflect.Pointer {
+		dest.Set(reflect.New(dest.Type().Elem()))
+		dest = dest.Elem()
+	}
+
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		if v := reflect.ValueOf(src); v.Type().AssignableTo(dest.Type()) {
+			dest.Set(v)
+			return nil
+		}
+		return fmt.Errorf("cannot scan %T into %s", src, dest.Type())
+	}
+
+	if s.json {
+		return decodeJSON(dest, data)
+	}
+	return dest.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(data)
+}
+
+// Decodes JSON into a Go value, mapping the fields of JSON objects to struct
+// fields in the same way as columns (i.e. via `bigquery:"name"` tags).
+func decodeJSON(dest reflect.Value, data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		dest.SetZero()
+		return nil
+	}
+
+	addr := dest.Addr()
+	if addr.Type().Implements(reflect.TypeFor[json.Unmarshaler]()) || addr.Type().Implements(textUnmarshalerType) {
+		return json.Unmarshal(data, addr.Interface())
+	}
+
+	switch t := dest.Type(); {
+	case t.Kind() == reflect.Pointer:
+		ptr := reflect.New(t.Elem())
+		if err := decodeJSON(ptr.Elem(), data); err != nil {
+			return err
+		}
+		dest.Set(ptr)
+		return nil
+	case t.Kind() == reflect.Struct:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		fields := structFieldMap(t)
+		dest.SetZero()
+		for name, value := range values {
+			index, ok := fields.lookup(name)
+			if !ok {
+				continue
+			}
+			if err := decodeJSON(dest.FieldByIndex(index), value); err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+		}
+		return nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, len(values), len(values))
+		for i, value := range values {
+			if err := decodeJSON(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		dest.Set(slice)
+		return nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(t, len(values))
+		for name, value := range values {
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeJSON(elem, value); err != nil {
+				return fmt.Errorf("field %s: %w", name, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(name).Convert(t.Key()), elem)
+		}
+		dest.Set(m)
+		return nil
+	}
+	return json.Unmarshal(data, addr.Interface())
+}
+
+// Maps lowercased column names to struct field index paths.
+type fieldMap struct {
+	tagged map[string][]int
+	named  map[string][]int
+}
+
+func (m fieldMap) lookup(name string) ([]int, bool) {
+	name = strings.ToLower(name)
+	if index, ok := m.tagged[name]; ok {
+		return index, true
+	}
+	index, ok := m.named[name]
+	return index, ok
+}
+
+func structFieldMap(t reflect.Type) fieldMap {
+	m := fieldMap{
+		tagged: map[string][]int{},
+		named:  map[string][]int{},
+	}
+	addStructFields(m, t, nil)
+	return m
+}
+
+func addStructFields(m fieldMap, t reflect.Type, prefix []int) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		index := append(append([]int(nil), prefix...), i)
+
+		tag := f.Tag.Get("bigquery")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		if name, _, _ := strings.Cut(tag, ","); name != "" && f.IsExported() {
+			// Outer fields take precedence over fields of embedded structs.
+			if _, ok := m.tagged[strings.ToLower(name)]; !ok {
+				m.tagged[strings.ToLower(name)] = index
+			}
+			continue
+		}
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			addStructFields(m, f.Type, index)
+			continue
+		}
+		if _, ok := m.named[strings.ToLower(f.Name)]; !ok && f.IsExported() {
+			m.named[strings.ToLower(f.Name)] = index
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+type scanCustomer struct {
+	Name  string `bigquery:"customer_name"`
+	Email *string
+}
+
+type scanItem struct {
+	SKU      string `bigquery:"sku"`
+	Quantity int
+}
+
+type scanOrder struct {
+	ID       int32 `bigquery:"id"`
+	Customer scanCustomer
+	Items    []scanItem
+	Tags     []string
+	Details  any
+	Note     string
+	Comment  sql.NullString
+	Discount *float64
+	Total    big.Rat
+	Ordered  civil.Date
+	Shipped  *civil.Date
+	Created  time.Time
+	Ignored  string `bigquery:"-"`
+}
+
+func cells(values ...any) map[string]any {
+	fields := make([]any, len(values))
+	for i, v := range values {
+		fields[i] = map[string]any{"v": v}
+	}
+	return map[string]any{"f": fields}
+}
+
+func TestCollectRows(t *testing.T) {
+	customer := &bq.TableFieldSchema{Name: "customer", Type: "RECORD", Fields: []*bq.TableFieldSchema{
+		{Name: "customer_name", Type: "STRING"},
+		{Name: "email", Type: "STRING"},
+	}}
+	items := &bq.TableFieldSchema{Name: "items", Type: "RECORD", Mode: "REPEATED", Fields: []*bq.TableFieldSchema{
+		{Name: "sku", Type: "STRING"},
+		{Name: "quantity", Type: "INTEGER"},
+	}}
+	schema := []*bq.TableFieldSchema{
+		{Name: "id", Type: "INTEGER"},
+		customer,
+		items,
+		{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+		{Name: "details", Type: "RECORD", Fields: []*bq.TableFieldSchema{{Name: "gift", Type: "BOOLEAN"}}},
+		{Name: "note", Type: "STRING"},
+		{Name: "comment", Type: "STRING"},
+		{Name: "discount", Type: "FLOAT"},
+		{Name: "total", Type: "NUMERIC"},
+		{Name: "ordered", Type: "DATE"},
+		{Name: "shipped", Type: "DATE"},
+		{Name: "created", Type: "TIMESTAMP"},
+	}
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: schema,
+			Rows: [][]any{
+				{
+					"1",
+					cells("Ada", "ada@example.com"),
+					[]any{map[string]any{"v": cells("a-1", "2")}, map[string]any{"v": cells("b-2", "1")}},
+					[]any{map[string]any{"v": "new"}},
+					cells("true"),
+					"fragile",
+					"leave at door",
+					"0.5",
+					"12.25",
+					"2024-03-01",
+					"2024-03-04",
+					"1709251200000000",
+				},
+				{"2", nil, []any{}, []any{}, nil, nil, nil, nil, "0", "2024-03-02", nil, "1709251200000000"},
+			},
+		}
+	})
+	db := server.open(server.config())
+
+	rows, err := db.QueryContext(context.Background(), "SELECT * FROM orders;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := CollectRows[scanOrder](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := "ada@example.com"
+	discount := 0.5
+	shipped := civil.Date{Year: 2024, Month: 3, Day: 4}
+	created := time.Unix(1709251200, 0).UTC()
+	want := []scanOrder{
+		{
+			ID:       1,
+			Customer: scanCustomer{Name: "Ada", Email: &email},
+			Items:    []scanItem{{SKU: "a-1", Quantity: 2}, {SKU: "b-2", Quantity: 1}},
+			Tags:     []string{"new"},
+			Details:  map[string]any{"gift": true},
+			Note:     "fragile",
+			Comment:  sql.NullString{String: "leave at door", Valid: true},
+			Discount: &discount,
+			Total:    *big.NewRat(49, 4),
+			Ordered:  civil.Date{Year: 2024, Month: 3, Day: 1},
+			Shipped:  &shipped,
+			Created:  created,
+		},
+		{
+			ID:      2,
+			Items:   []scanItem{},
+			Tags:    []string{},
+			Total:   *new(big.Rat),
+			Ordered: civil.Date{Year: 2024, Month: 3, Day: 2},
+			Created: created,
+		},
+	}
+	if len(orders) != len(want) {
+		t.Fatalf("got %d orders, want %d", len(orders), len(want))
+	}
+	for i := range want {
+		got := orders[i]
+		if got.Total.Cmp(&want[i].Total) != 0 {
+			t.Errorf("order %d: Total = %s, want %s", i, got.Total.RatString(), want[i].Total.RatString())
+		}
+		got.Total, want[i].Total = big.Rat{}, big.Rat{}
+		if !got.Created.Equal(want[i].Created) {
+			t.Errorf("order %d: Created = %s, want %s", i, got.Created, want[i].Created)
+		}
+		got.Created, want[i].Created = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("order %d:\ngot  %+v\nwant %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestScanStructErrors(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			Schema: []*bq.TableFieldSchema{{Name: "id", Type: "INTEGER"}, {Name: "extra", Type: "STRING"}},
+			Rows:   [][]any{{"1", "x"}},
+		}
+	})
+	db := server.open(server.config())
+	ctx := context.Background()
+
+	rows, err := db.QueryContext(ctx, "SELECT * FROM t;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CollectRows[struct{ ID int }](rows); err == nil {
+		t.Error("CollectRows with an unmapped column: want an error")
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT * FROM t;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	if _, err := ScanStruct[int](rows); err == nil {
+		t.Error("ScanStruct into an int: want an error")
+	}
+	if _, err := ScanStruct[struct {
+		ID    int
+		Extra int
+	}](rows); err == nil {
+		t.Error("ScanStruct of a STRING into an int: want an error")
+	}
+	got, err := ScanStruct[*struct {
+		ID    int
+		Extra string
+	}](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 1 || got.Extra != "x" {
+		t.Errorf("ScanStruct = %+v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package bigquery
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// BigQuery terminates sessions after 24 hours of inactivity, and after 7
+	// days regardless of activity.
+	sessionIdleTimeout = 24 * time.Hour
+	sessionMaxAge      = 7 * 24 * time.Hour
+
+	// Connections are discarded this long before their session would
+	// expire, so that statements aren't run in sessions about to expire.
+	sessionExpiryMargin = 10 * time.Minute
+)
+
+// Records the session the latest job ran in (if any).
+func (c *conn) updateSession(sessionID string) {
+	now := time.Now()
+	if sessionID != "" && sessionID != c.sessionID {
+		c.sessionID = sessionID
+		c.sessionCreated = now
+	}
+	if c.sessionID != "" {
+		c.sessionUsed = now
+	}
+}
+
+// Reports whether the connection's session has expired, or is about to.
+func (c *conn) sessionExpiring() bool {
+	if c.sessionID == "" {
+		return false
+	}
+	now := time.Now().Add(sessionExpiryMargin)
+	return now.Sub(c.sessionUsed) >= sessionIdleTimeout ||
+		now.Sub(c.sessionCreated) >= sessionMaxAge
+}
+
+// Forgets the connection's session, along with its state.
+func (c *conn) forgetSession() {
+	c.sessionID = ""
+	c.sessionCreated = time.Time{}
+	c.sessionUsed = time.Time{}
+	c.variables = nil
+}
+
+// Forgets the connection's expired session (which therefore isn't aborted
+// when the connection is closed), and marks the connection as invalid.
+func (s *stmt) checkSessionError(err error) {
+	if sessionError(s.conn.sessionID, err) {
+		s.conn.forgetSession()
+		s.conn.invalid = true
+	}
+}
+
+// Reports whether the statement should be re-run in a new session, since it
+// failed because its session (with the given ID) expired. This is only safe
+// outside of transactions, since the transaction is lost along with the
+// session.
+func (s *stmt) retryInNewSession(sessionID string, txStmt txStatement, err error) bool {
+	c := s.conn
+	if s.internal || txStmt != txStatementNone || c.txState != txNone {
+		return false
+	}
+	if !c.invalid || c.sessionID != "" || !sessionError(sessionID, err) {
+		return false
+	}
+	c.invalid = false
+	return true
+}
+
+// Reports whether the error indicates that the session has expired (or has
+// otherwise been terminated).
+func sessionError(sessionID string, err error) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	var messages []string
+
+	var bqErr *bigquery.Error
+	if errors.As(err, &bqErr) {
+		messages = append(messages, bqErr.Message)
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest {
+		messages = append(messages, apiErr.Message)
+		for _, errItem := range apiErr.Errors {
+			messages = append(messages, errItem.Message)
+		}
+	}
+
+	for _, msg := range messages {
+		msg = strings.ToLower(msg)
+		if !strings.Contains(msg, "session") {
+			continue
+		}
+		if strings.Contains(msg, "expired") ||
+			strings.Contains(msg, "no longer available") ||
+			strings.Contains(msg, "terminated") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package bigquery
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+var _ SessionConn = (*conn)(nil)
+
+// SessionConn provides access to the state of a connection's BigQuery
+// session. It's implemented by the driver's connections, which can be
+// accessed via [sql.Conn.Raw]:
+//
+//	err := conn.Raw(func(driverConn any) error {
+//		tables, err := driverConn.(bigquery.SessionConn).TempTables(ctx)
+//		...
+//	})
+type SessionConn interface {
+	// SessionID returns the ID of the connection's session, or "" if the
+	// session hasn't been created yet.
+	SessionID() string
+
+	// TempTables returns the names of the session's temporary tables, as
+	// listed by the session's INFORMATION_SCHEMA.TABLES view.
+	TempTables(ctx context.Context) ([]string, error)
+
+	// Variables returns the names of the variables declared (via DECLARE
+	// statements) in the session. BigQuery doesn't expose session variables
+	// via INFORMATION_SCHEMA views, so they're tracked by the driver.
+	Variables() []string
+}
+
+func (c *conn) SessionID() string {
+	return c.sessionID
+}
+
+func (c *conn) TempTables(ctx context.Context) ([]string, error) {
+	if c.sessionID == "" {
+		return nil, nil
+	}
+
+	query := &stmt{
+		conn:     c,
+		query:    "SELECT table_name FROM _SESSION.INFORMATION_SCHEMA.TABLES ORDER BY table_name;",
+		internal: true,
+	}
+	_, it, err := query.iterator(ctx, options{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			return tables, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := row[0].(string); ok {
+			tables = append(tables, name)
+		}
+	}
+}
+
+func (c *conn) Variables() []string {
+	return slices.Clone(c.variables)
+}
+
+// Matches a DECLARE statement, capturing the declared variable names.
+var declareRegexp = regexp.MustCompile(`(?i)^\s*DECLARE\s+([a-z_][a-z0-9_]*(?:\s*,\s*[a-z_][a-z0-9_]*)*)`)
+
+// Records the variables declared by the (successfully run) query. Only the
+// DECLARE statements at the start of the query declare session variables;
+// any others are in BEGIN ... END blocks, and go out of scope with them.
+func (c *conn) recordVariables(query string) {
+	if c.sessionID == "" {
+		return
+	}
+	for _, statement := range splitStatements(query) {
+		match := declareRegexp.FindStringSubmatch(statement)
+		if match == nil {
+			break
+		}
+		for _, name := range strings.Split(match[1], ",") {
+			name = strings.TrimSpace(name)
+			if !slices.ContainsFunc(c.variables, func(v string) bool { return strings.EqualFold(v, name) }) {
+				c.variables = append(c.variables, name)
+			}
+		}
+	}
+}
+
+// Runs the configured setup statements (see [Config.SessionSetup]) if the
+// connection doesn't have a session yet, which creates a new session. If a
+// setup statement fails, the connection is discarded, since its session is
+// only partially set up.
+func (c *conn) setupSession(ctx context.Context) error {
+	if c.sessionID != "" {
+		return nil
+	}
+	for _, query := range c.config.SessionSetup {
+		setup := &stmt{conn: c, query: query, internal: true}
+		if _, _, err := setup.run(ctx, options{}, nil); err != nil {
+			c.invalid = true
+			return err
+		}
+	}
+	return nil
+}
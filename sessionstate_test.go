@@ -0,0 +1,94 @@
+package bigquery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT 1", []string{"SELECT 1"}},
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", " SELECT 2"}},
+		{"SELECT ';'; SELECT \"a;b\"", []string{"SELECT ';'", ` SELECT "a;b"`}},
+		{"SELECT '''x;\ny'''; SELECT `a;b`", []string{"SELECT '''x;\ny'''", " SELECT `a;b`"}},
+		{"SELECT 'it\\'s;'; SELECT 2", []string{"SELECT 'it\\'s;'", " SELECT 2"}},
+		{"-- a; b\nSELECT 1; /* c; d */ SELECT 2 # e; f", []string{" SELECT 1", "   SELECT 2  "}},
+	}
+	for _, test := range tests {
+		if got := splitStatements(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("splitStatements(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestRecordVariables(t *testing.T) {
+	c := &conn{sessionID: "session"}
+	c.recordVariables("DECLARE a STRING DEFAULT 'x;DECLARE b INT64'; DECLARE c, d INT64; SELECT 1; DECLARE e INT64;")
+	c.recordVariables("-- comment; DECLARE f INT64\nDECLARE A INT64; DECLARE g DEFAULT \"y;z\";")
+	if got, want := c.Variables(), []string{"a", "c", "d", "g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %q, want %q", got, want)
+	}
+
+	c = &conn{}
+	c.recordVariables("DECLARE a INT64;")
+	if got := c.Variables(); len(got) != 0 {
+		t.Errorf("Variables() without a session = %q, want none", got)
+	}
+}
+
+func TestTempTables(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		if q.SQL == "SELECT table_name FROM _SESSION.INFORMATION_SCHEMA.TABLES ORDER BY table_name;" {
+			return fakeResult{
+				Schema: []*bq.TableFieldSchema{{Name: "table_name", Type: "STRING"}},
+				Rows:   [][]any{{"a"}, {"b"}},
+			}
+		}
+		return fakeResult{}
+	})
+	config := server.config()
+	config.SessionSetup = []string{"CREATE TEMP TABLE a (x INT64);"}
+	db := server.open(config)
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.Raw(func(driverConn any) error {
+		tables, err := driverConn.(SessionConn).TempTables(ctx)
+		if err != nil || tables != nil {
+			t.Errorf("TempTables() before the session is created = %q, %v, want none", tables, err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "CREATE TEMP TABLE b (y INT64);"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Raw(func(driverConn any) error {
+		session := driverConn.(SessionConn)
+		tables, err := session.TempTables(ctx)
+		if err != nil {
+			return err
+		}
+		if want := []string{"a", "b"}; !reflect.DeepEqual(tables, want) {
+			t.Errorf("TempTables() = %q, want %q", tables, want)
+		}
+		if session.SessionID() != "session" {
+			t.Errorf("SessionID() = %q, want %q", session.SessionID(), "session")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
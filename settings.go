@@ -0,0 +1,160 @@
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// QuerySettings configure how queries are run. Defaults can be set via
+// [Config] (and the DSN), and overridden per query via [WithQuerySettings].
+//
+// The applied settings are recorded in the job labels (as bq_priority,
+// bq_reservation, bq_kms_key and bq_max_slots) for auditing.
+type QuerySettings struct {
+	// Priority is the priority of the query jobs (interactive by default).
+	Priority bigquery.QueryPriority
+
+	// Reservation is the reservation the query jobs should run in (of the
+	// form projects/PROJECT/locations/LOCATION/reservations/RESERVATION, or
+	// "none" for on-demand capacity).
+	Reservation string
+
+	// KMSKeyName is the Cloud KMS key used to encrypt the destination table
+	// of the query jobs (of the form
+	// projects/PROJECT/locations/LOCATION/keyRings/RING/cryptoKeys/KEY).
+	KMSKeyName string
+
+	// MaxSlots is a target limit on the number of slots the query jobs should
+	// use.
+	MaxSlots int
+}
+
+type querySettingsKey struct{}
+
+// WithQuerySettings returns a copy of the context with the given
+// [QuerySettings] attached. The non-zero settings override the defaults
+// configured via [Config] (and any settings attached to the parent context)
+// for every query executed with the returned context.
+func WithQuerySettings(ctx context.Context, settings QuerySettings) context.Context {
+	return context.WithValue(ctx, querySettingsKey{}, querySettingsFromContext(ctx).merge(settings))
+}
+
+func querySettingsFromContext(ctx context.Context) QuerySettings {
+	settings, _ := ctx.Value(querySettingsKey{}).(QuerySettings)
+	return settings
+}
+
+// Returns the settings, overridden by the non-zero overrides.
+func (s QuerySettings) merge(overrides QuerySettings) QuerySettings {
+	if overrides.Priority != "" {
+		s.Priority = overrides.Priority
+	}
+	if overrides.Reservation != "" {
+		s.Reservation = overrides.Reservation
+	}
+	if overrides.KMSKeyName != "" {
+		s.KMSKeyName = overrides.KMSKeyName
+	}
+	if overrides.MaxSlots != 0 {
+		s.MaxSlots = overrides.MaxSlots
+	}
+	return s
+}
+
+var (
+	reservationRegexp = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/reservations/[^/]+$`)
+	kmsKeyNameRegexp  = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+)
+
+func (s QuerySettings) validate() error {
+	switch s.Priority {
+	case "", bigquery.InteractivePriority, bigquery.BatchPriority:
+	default:
+		return fmt.Errorf("invalid priority: %s", s.Priority)
+	}
+	if s.Reservation != "" && s.Reservation != "none" && !reservationRegexp.MatchString(s.Reservation) {
+		return fmt.Errorf("invalid reservation: %s", s.Reservation)
+	}
+	if s.KMSKeyName != "" && !kmsKeyNameRegexp.MatchString(s.KMSKeyName) {
+		return fmt.Errorf("invalid KMS key name: %s", s.KMSKeyName)
+	}
+	if s.MaxSlots < 0 || s.MaxSlots > math.MaxInt32 {
+		return fmt.Errorf("invalid max slots: %d", s.MaxSlots)
+	}
+	return nil
+}
+
+func parsePriority(priority string) (bigquery.QueryPriority, error) {
+	switch p := bigquery.QueryPriority(strings.ToUpper(priority)); p {
+	case bigquery.InteractivePriority, bigquery.BatchPriority:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid priority: %s", priority)
+	}
+}
+
+// Applies the settings to the query, and records them in its labels (which
+// must not already be set).
+func (s QuerySettings) apply(query *bigquery.Query) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	query.Priority = s.Priority
+	query.Reservation = s.Reservation
+	query.MaxSlots = int32(s.MaxSlots)
+	if s.KMSKeyName != "" {
+		query.DestinationEncryptionConfig = &bigquery.EncryptionConfig{KMSKeyName: s.KMSKeyName}
+	}
+
+	labels := map[string]string{}
+	if s.Priority != "" {
+		labels["bq_priority"] = string(s.Priority)
+	}
+	if s.Reservation != "" {
+		labels["bq_reservation"] = s.Reservation
+	}
+	if s.KMSKeyName != "" {
+		labels["bq_kms_key"] = s.KMSKeyName
+	}
+	if s.MaxSlots != 0 {
+		labels["bq_max_slots"] = strconv.Itoa(s.MaxSlots)
+	}
+	for key, value := range labels {
+		if _, ok := query.Labels[key]; ok {
+			return fmt.Errorf("label %s is reserved for query settings", key)
+		}
+		if query.Labels == nil {
+			query.Labels = map[string]string{}
+		}
+		query.Labels[key] = labelValue(value)
+	}
+	return validateLabels(query.Labels)
+}
+
+// Converts a resource name (e.g. a reservation or KMS key name) into a valid
+// label value, by lowercasing it and replacing the characters labels don't
+// allow. Names longer than labels allow keep their last part, prefixed with a
+// hash of the full name (so that different names don't collide).
+func labelValue(value string) string {
+	label := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(value))
+	if len(label) > maxLabelLength {
+		hash := sha256.Sum256([]byte(value))
+		prefix := hex.EncodeToString(hash[:4]) + "-"
+		label = prefix + label[len(label)-(maxLabelLength-len(prefix)):]
+	}
+	return label
+}
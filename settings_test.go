@@ -0,0 +1,37 @@
+package bigquery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"projects/p/locations/US/reservations/prod", "projects_p_locations_us_reservations_prod"},
+		{"my-reservation", "my-reservation"},
+	}
+	for _, test := range tests {
+		if got := labelValue(test.value); got != test.want {
+			t.Errorf("labelValue(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+
+	// Long names keep their end, and names that only differ in their
+	// (truncated) start don't collide.
+	a := labelValue("projects/project-a/locations/europe-west1/keyRings/ring/cryptoKeys/key")
+	b := labelValue("projects/project-b/locations/europe-west1/keyRings/ring/cryptoKeys/key")
+	for _, label := range []string{a, b} {
+		if !labelValueRegexp.MatchString(label) || len(label) != maxLabelLength {
+			t.Errorf("labelValue returned an invalid or short label: %q", label)
+		}
+		if !strings.HasSuffix(label, "_keyrings_ring_cryptokeys_key") {
+			t.Errorf("labelValue(...) = %q, want it to keep the end of the name", label)
+		}
+	}
+	if a == b {
+		t.Errorf("labelValue returned %q for different names", a)
+	}
+}
@@ -0,0 +1,88 @@
+package bigquery
+
+import (
+	"errors"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// JobStats summarizes the statistics of a completed query job.
+type JobStats struct {
+	// Job is the underlying job.
+	Job *bigquery.Job
+
+	JobID    string
+	Location string
+
+	CreationTime time.Time
+	StartTime    time.Time
+	EndTime      time.Time
+
+	TotalBytesProcessed int64
+	TotalBytesBilled    int64
+	CacheHit            bool
+	SlotMillis          int64
+
+	// StatementType is the type of the query's statement (e.g. "SELECT" or
+	// "INSERT"), or "SCRIPT" for multi-statement queries.
+	StatementType string
+
+	// NumDMLAffectedRows and DMLStats are only set for DML statements.
+	NumDMLAffectedRows int64
+	DMLStats           *bigquery.DMLStatistics
+
+	ReferencedTables []*bigquery.Table
+	QueryPlan        []*bigquery.ExplainQueryStage
+	Timeline         []*bigquery.QueryTimelineSample
+}
+
+// ErrNoStats is returned when no job statistics are available.
+var ErrNoStats = errors.New("no job statistics available")
+
+// CollectStats returns a [GetJob] function which stores the statistics of the
+// job in stats. As with any GetJob function, it can be passed as an argument
+// to a Query or Exec method, or attached to a context via [WithGetJob] (in
+// which case stats holds those of the last job run with the context):
+//
+//	var stats bigquery.JobStats
+//	rows, err := db.QueryContext(ctx, "SELECT * FROM my_table;", bigquery.CollectStats(&stats))
+func CollectStats(stats *JobStats) GetJob {
+	return func(job *bigquery.Job) {
+		if s, err := jobStats(job); err == nil {
+			*stats = *s
+		}
+	}
+}
+
+func jobStats(job *bigquery.Job) (*JobStats, error) {
+	if job == nil {
+		return nil, ErrNoStats
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return nil, ErrNoStats
+	}
+
+	stats := &JobStats{
+		Job:                 job,
+		JobID:               job.ID(),
+		Location:            job.Location(),
+		CreationTime:        status.Statistics.CreationTime,
+		StartTime:           status.Statistics.StartTime,
+		EndTime:             status.Statistics.EndTime,
+		TotalBytesProcessed: status.Statistics.TotalBytesProcessed,
+	}
+	if details := queryStatistics(job); details != nil {
+		stats.TotalBytesBilled = details.TotalBytesBilled
+		stats.CacheHit = details.CacheHit
+		stats.SlotMillis = details.SlotMillis
+		stats.StatementType = details.StatementType
+		stats.NumDMLAffectedRows = details.NumDMLAffectedRows
+		stats.DMLStats = details.DMLStats
+		stats.ReferencedTables = details.ReferencedTables
+		stats.QueryPlan = details.QueryPlan
+		stats.Timeline = details.Timeline
+	}
+	return stats, nil
+}
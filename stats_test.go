@@ -0,0 +1,37 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestCollectStats(t *testing.T) {
+	server := newFakeServer(t, func(q fakeQuery) fakeResult {
+		return fakeResult{
+			DMLAffected: 3,
+			Stats:       &bq.JobStatistics2{CacheHit: true},
+		}
+	})
+	db := server.open(server.config())
+	ctx := context.Background()
+
+	var stats JobStats
+	if _, err := db.ExecContext(ctx, "UPDATE t SET x = 1 WHERE true;", CollectStats(&stats)); err != nil {
+		t.Fatal(err)
+	}
+	if stats.JobID == "" || stats.StatementType != "UPDATE" || stats.NumDMLAffectedRows != 3 || !stats.CacheHit {
+		t.Errorf("CollectStats as an argument: got %+v", stats)
+	}
+
+	stats = JobStats{}
+	rows, err := db.QueryContext(WithGetJob(ctx, CollectStats(&stats)), "SELECT 1;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if stats.JobID == "" || stats.StatementType != "SELECT" {
+		t.Errorf("CollectStats via the context: got %+v", stats)
+	}
+}
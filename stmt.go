@@ -2,13 +2,12 @@ package bigquery
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
-	"fmt"
-	"net/http"
+	"maps"
 
 	"cloud.google.com/go/bigquery"
-	"google.golang.org/api/googleapi"
 )
 
 var (
@@ -18,8 +17,9 @@ var (
 )
 
 type stmt struct {
-	conn  *conn
-	query string
+	conn     *conn
+	query    string
+	internal bool
 }
 
 func (s *stmt) Close() error {
@@ -39,56 +39,145 @@ func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
 }
 
 func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	iterator, err := s.iterator(ctx, args)
+	opts, args := extractOptions(args)
+	outs, args, err := extractOutParams(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var job *bigquery.Job
+	var iterator *bigquery.RowIterator
+	if len(outs) > 0 {
+		job, iterator, err = s.execCall(ctx, opts, outs, args)
+	} else {
+		job, iterator, err = s.iterator(ctx, opts, args)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	return &result{
-		iterator: iterator,
+		job:         job,
+		iterator:    iterator,
+		destination: opts.destinationOrContext(ctx),
 	}, nil
 }
 
 func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	iterator, err := s.iterator(ctx, args)
+	opts, args := extractOptions(args)
+	for _, arg := range args {
+		if _, ok := arg.Value.(sql.Out); ok {
+			return nil, errors.New("OUT parameters are only supported by Exec")
+		}
+	}
+
+	job, iterator, err := s.iterator(ctx, opts, args)
 	if err != nil {
 		return nil, err
 	}
 
 	return &rows{
-		iterator: iterator,
+		job:        job,
+		iterator:   iterator,
+		conversion: newConversion(s.conn.config),
 	}, nil
 }
 
-func (s *stmt) iterator(ctx context.Context, args []driver.NamedValue) (*bigquery.RowIterator, error) {
+// Runs the statement, returning the completed job and an iterator over its
+// results (which is nil for dry runs).
+func (s *stmt) iterator(ctx context.Context, opts options, args []driver.NamedValue) (*bigquery.Job, *bigquery.RowIterator, error) {
 	if s.conn.invalid {
-		return nil, driver.ErrBadConn
+		return nil, nil, driver.ErrBadConn
+	}
+
+	txStmt := parseTxStatement(s.query)
+	if err := s.conn.checkTxStatement(txStmt, s.internal); err != nil {
+		return nil, nil, err
+	}
+
+	sessionID := s.conn.sessionID
+	job, iterator, err := s.runInSession(ctx, opts, args)
+	if err != nil && s.retryInNewSession(sessionID, txStmt, err) {
+		job, iterator, err = s.runInSession(withSessionRetry(ctx), opts, args)
+	}
+	if err := s.conn.updateTxState(txStmt, err); err != nil {
+		return nil, nil, err
+	}
+	return job, iterator, nil
+}
+
+// Runs the statement, after setting up a new session if necessary.
+func (s *stmt) runInSession(ctx context.Context, opts options, args []driver.NamedValue) (*bigquery.Job, *bigquery.RowIterator, error) {
+	if err := s.conn.setupSession(ctx); err != nil {
+		return nil, nil, err
 	}
+	return s.run(ctx, opts, args)
+}
 
-	query := s.buildQuery(args)
-	s.conn.getQueryOpt(query)
+func (s *stmt) run(ctx context.Context, opts options, args []driver.NamedValue) (*bigquery.Job, *bigquery.RowIterator, error) {
+	query, err := s.buildQuery(ctx, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Options (including those set via the context) only apply to the user's
+	// statements, and not to e.g. transaction control statements.
+	if !s.internal {
+		if err := s.applyOptions(ctx, query, opts); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	job, err := query.Run(ctx)
+	invoker := chainInterceptors(s.conn.config.Interceptors, s.conn.limiter.wrap(runQuery))
+	job, err := invoker(ctx, query)
+	if err != nil && duplicateJobError(err) && query.JobID != "" && !query.AddJobIDSuffix && !query.DryRun {
+		// The job already exists (e.g. since the statement is being retried),
+		// so attach to it rather than running the statement again. Since the
+		// job may have run in another session, it doesn't affect ours.
+		job, err = s.conn.attachJob(ctx, query)
+	} else if job != nil && !query.DryRun {
+		s.conn.updateSession(getSessionID(job))
+	}
 	if err != nil {
 		s.checkSessionError(err)
-		return nil, err
+		return nil, nil, err
 	}
-	s.conn.getJobOpt(job)
-
-	if query.DryRun {
-		return nil, nil
+	if !s.internal {
+		opts.getJobOpt(job)
+		if getJob := getJobFromContext(ctx); getJob != nil {
+			getJob(job)
+		}
 	}
 
-	if sessionID := getSessionID(job); sessionID != "" {
-		s.conn.sessionID = sessionID
+	if query.DryRun {
+		return job, nil, nil
 	}
+	s.conn.recordVariables(s.query)
 
 	iterator, err := job.Read(ctx)
 	if err != nil {
 		s.checkSessionError(err)
-		return nil, err
+		return nil, nil, err
+	}
+	return job, iterator, nil
+}
+
+// Applies the destination, external tables and query hooks of the statement,
+// which are passed as arguments or set via the context.
+func (s *stmt) applyOptions(ctx context.Context, query *bigquery.Query, opts options) error {
+	if destination := opts.destinationOrContext(ctx); destination != nil {
+		if err := destination.apply(query, s.conn); err != nil {
+			return err
+		}
+	}
+	external := mergeExternalTables(externalTablesFromContext(ctx), opts.external)
+	if err := external.apply(query); err != nil {
+		return err
 	}
-	return iterator, nil
+	opts.getQueryOpt(query)
+	if getQuery := getQueryFromContext(ctx); getQuery != nil {
+		getQuery(query)
+	}
+	return nil
 }
 
 func getSessionID(job *bigquery.Job) string {
@@ -105,14 +194,63 @@ func getSessionID(job *bigquery.Job) string {
 	return status.Statistics.SessionInfo.SessionID
 }
 
-func (s *stmt) buildQuery(args []driver.NamedValue) *bigquery.Query {
-	query := s.conn.client.Query(s.query)
+func (s *stmt) buildQuery(ctx context.Context, args []driver.NamedValue) (*bigquery.Query, error) {
+	text, args := bindGeographyParams(s.query, args)
+	query := s.conn.client.Query(text)
+	query.DefaultProjectID = s.conn.config.DatasetProject
 	query.DefaultDatasetID = s.conn.config.Dataset
+	// Internal and transaction control statements aren't dry run, since the
+	// connection's session and transaction state depend on them.
+	query.DryRun = s.conn.config.DryRun && !s.internal && parseTxStatement(s.query) == txStatementNone
 	query.Parameters = s.buildParameters(args)
 	query.ConnectionProperties = s.buildConnectionProperties()
 	query.CreateSession = s.conn.sessionID == ""
 
-	return query
+	labels, err := s.buildLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query.Labels = labels
+
+	settings := s.conn.config.QuerySettings.merge(querySettingsFromContext(ctx))
+	if err := settings.apply(query); err != nil {
+		return nil, err
+	}
+
+	// Deterministic job IDs only apply to the user's statements (and not to
+	// e.g. session setup or transaction control statements).
+	jobID := ""
+	if !s.internal {
+		if jobID, err = deterministicJobID(ctx, s.query, query.Parameters); err != nil {
+			return nil, err
+		}
+	}
+	if jobID != "" {
+		query.JobID = jobID
+	} else if prefix := jobIDPrefixFromContext(ctx); prefix != "" {
+		if err := validateJobIDPrefix(prefix); err != nil {
+			return nil, err
+		}
+		query.JobID = prefix
+		query.AddJobIDSuffix = true
+	}
+
+	return query, nil
+}
+
+func (s *stmt) buildLabels(ctx context.Context) (map[string]string, error) {
+	defaults, overrides := s.conn.config.Labels, labelsFromContext(ctx)
+	if len(defaults) == 0 && len(overrides) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(defaults)+len(overrides))
+	maps.Copy(labels, defaults)
+	maps.Copy(labels, overrides)
+	if err := validateLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
 }
 
 func (s *stmt) buildParameters(args []driver.NamedValue) []bigquery.QueryParameter {
@@ -137,24 +275,3 @@ func (s *stmt) buildConnectionProperties() []*bigquery.ConnectionProperty {
 		},
 	}
 }
-
-func (s *stmt) checkSessionError(err error) {
-	if sessionError(s.conn.sessionID, err) {
-		s.conn.invalid = true
-	}
-}
-
-func sessionError(sessionID string, err error) bool {
-	var bqErr *googleapi.Error
-	if !errors.As(err, &bqErr) || bqErr.Code != http.StatusBadRequest {
-		return false
-	}
-
-	sessionBrokenMsg := fmt.Sprintf("Session %s has expired and is no longer available.", sessionID)
-	for _, errItem := range bqErr.Errors {
-		if errItem.Reason == "resourcesExceeded" && errItem.Message == sessionBrokenMsg {
-			return true
-		}
-	}
-	return false
-}
@@ -2,23 +2,223 @@ package bigquery
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
 )
 
 var (
 	_ driver.Tx = (*tx)(nil)
 )
 
+// ErrTxAborted is returned when BigQuery has aborted the current transaction
+// (e.g. due to a conflicting concurrent update). Once a transaction has been
+// aborted, every statement other than a rollback fails with this error.
+var ErrTxAborted = errors.New("transaction aborted")
+
 type tx struct {
 	conn *conn
 }
 
 func (t *tx) Commit() error {
-	_, err := t.conn.ExecContext(context.Background(), "COMMIT TRANSACTION;", nil)
+	if t.conn.tx != t {
+		return sql.ErrTxDone
+	}
+	defer t.done()
+	_, err := t.conn.execTxStatement(context.Background(), "COMMIT TRANSACTION;")
 	return err
 }
 
 func (t *tx) Rollback() error {
-	_, err := t.conn.ExecContext(context.Background(), "ROLLBACK TRANSACTION;", nil)
+	if t.conn.tx != t {
+		return sql.ErrTxDone
+	}
+	defer t.done()
+	return t.conn.rollbackTx(context.Background())
+}
+
+func (t *tx) done() {
+	if t.conn.tx == t {
+		t.conn.tx = nil
+	}
+}
+
+type txState int
+
+const (
+	txNone txState = iota
+	txActive
+	txAborted
+)
+
+type txStatement int
+
+const (
+	txStatementNone txStatement = iota
+	txStatementBegin
+	txStatementCommit
+	txStatementRollback
+)
+
+// Returns the kind of transaction control statement the query consists of, if
+// any. Only queries consisting of a single BEGIN, COMMIT or ROLLBACK statement
+// are recognized, since scripts containing transactions are self-contained.
+// In particular, a BEGIN followed by other statements starts a block
+// (BEGIN...END) rather than a transaction.
+func parseTxStatement(query string) txStatement {
+	query = strings.TrimRight(stripComments(query), " \t\r\n;")
+	if strings.Contains(query, ";") {
+		return txStatementNone
+	}
+	fields := strings.Fields(strings.ToUpper(query))
+	if len(fields) == 0 || len(fields) > 2 {
+		return txStatementNone
+	}
+	if len(fields) == 2 && fields[1] != "TRANSACTION" {
+		return txStatementNone
+	}
+
+	switch fields[0] {
+	case "BEGIN":
+		return txStatementBegin
+	case "COMMIT":
+		return txStatementCommit
+	case "ROLLBACK":
+		return txStatementRollback
+	default:
+		return txStatementNone
+	}
+}
+
+// Removes "--", "#" and "/* */" style comments from the query. String
+// literals aren't taken into account, which is fine for our purposes, since
+// transaction control statements never contain them.
+func stripComments(query string) string {
+	var b strings.Builder
+	for i := 0; i < len(query); i++ {
+		switch {
+		case strings.HasPrefix(query[i:], "--"), query[i] == '#':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end
+			b.WriteByte('\n')
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(query[i])
+		}
+	}
+	return b.String()
+}
+
+// Checks whether the given statement may be executed in the current
+// transaction state. Transaction control statements issued by the driver
+// itself (via BeginTx/Commit/Rollback) are marked as internal.
+func (c *conn) checkTxStatement(stmt txStatement, internal bool) error {
+	switch stmt {
+	case txStatementBegin:
+		if c.txState != txNone {
+			return errors.New("transaction already in progress (nested transactions are not supported)")
+		}
+	case txStatementCommit, txStatementRollback:
+		if c.tx != nil && !internal {
+			return errors.New("transaction must be committed or rolled back via driver.Tx")
+		}
+	}
+
+	if c.txState == txAborted && stmt != txStatementRollback {
+		return ErrTxAborted
+	}
+	return nil
+}
+
+// Updates the transaction state after a statement has been executed, and
+// returns the error that should be reported to the caller.
+func (c *conn) updateTxState(stmt txStatement, err error) error {
+	if err == nil {
+		switch stmt {
+		case txStatementBegin:
+			c.txState = txActive
+		case txStatementCommit, txStatementRollback:
+			c.txState = txNone
+		}
+		return nil
+	}
+
+	if c.txState == txActive && txAbortedError(err) {
+		c.txState = txAborted
+		return fmt.Errorf("%w: %w", ErrTxAborted, err)
+	}
 	return err
 }
+
+func (c *conn) execTxStatement(ctx context.Context, query string) (driver.Result, error) {
+	statement := &stmt{
+		conn:     c,
+		query:    query,
+		internal: true,
+	}
+	return statement.ExecContext(ctx, nil)
+}
+
+// Rolls back the current transaction, if any. If the rollback fails, the
+// server-side transaction state is unknown, so the connection is marked as
+// invalid to prevent it from being reused. Errors are not reported for
+// transactions that had already been aborted by BigQuery. Either way, the
+// driver.Tx of the transaction (if any) is done.
+func (c *conn) rollbackTx(ctx context.Context) error {
+	c.tx = nil
+	if c.txState == txNone {
+		return nil
+	}
+
+	aborted := c.txState == txAborted
+	if _, err := c.execTxStatement(ctx, "ROLLBACK TRANSACTION;"); err != nil {
+		c.txState = txNone
+		c.invalid = true
+		if aborted {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Reports whether the error indicates that BigQuery aborted the transaction
+// the failing statement was part of.
+func txAbortedError(err error) bool {
+	var messages []string
+
+	var bqErr *bigquery.Error
+	if errors.As(err, &bqErr) {
+		messages = append(messages, bqErr.Message)
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		messages = append(messages, apiErr.Message)
+		for _, errItem := range apiErr.Errors {
+			messages = append(messages, errItem.Message)
+		}
+	}
+
+	for _, msg := range messages {
+		msg = strings.ToLower(msg)
+		if strings.Contains(msg, "transaction") && strings.Contains(msg, "abort") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package bigquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestParseTxStatement(t *testing.T) {
+	tests := []struct {
+		query string
+		want  txStatement
+	}{
+		{"BEGIN", txStatementBegin},
+		{"begin transaction;", txStatementBegin},
+		{"-- start\nBEGIN TRANSACTION ;\n", txStatementBegin},
+		{"COMMIT", txStatementCommit},
+		{"COMMIT TRANSACTION;", txStatementCommit},
+		{"ROLLBACK /* undo */ TRANSACTION", txStatementRollback},
+		{"BEGIN; SELECT 1; END", txStatementNone},
+		{"BEGIN\nSELECT 1;\nEND;", txStatementNone},
+		{"BEGIN;END", txStatementNone},
+		{"BEGIN; COMMIT;", txStatementNone},
+		{"BEGIN WORK", txStatementNone},
+		{"SELECT 1", txStatementNone},
+		{"", txStatementNone},
+	}
+	for _, test := range tests {
+		if got := parseTxStatement(test.query); got != test.want {
+			t.Errorf("parseTxStatement(%q) = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestStaleTx(t *testing.T) {
+	c := &conn{}
+	stale := &tx{conn: c}
+	c.tx = stale
+
+	// E.g. when the connection is reset after the transaction was aborted.
+	if err := c.rollbackTx(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if c.tx != nil {
+		t.Error("rollbackTx didn't clear the transaction")
+	}
+	if err := stale.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Commit = %v, want %v", err, sql.ErrTxDone)
+	}
+	if err := stale.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Rollback = %v, want %v", err, sql.ErrTxDone)
+	}
+}
@@ -0,0 +1,180 @@
+package bigquery
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+)
+
+// TypeMapping selects the policy used to convert BigQuery values to Go values.
+type TypeMapping string
+
+const (
+	// TypeMappingStrict maps BigQuery types to Go types as described in the
+	// README (the default), and fails when a value has an unexpected type.
+	TypeMappingStrict TypeMapping = "strict"
+
+	// TypeMappingLenient uses the same mapping as TypeMappingStrict, but
+	// converts values of unexpected or unsupported types to strings instead of
+	// failing.
+	TypeMappingLenient TypeMapping = "lenient"
+
+	// TypeMappingNative returns more specific Go types where possible: DATE
+	// and DATETIME values are returned as time.Time values (in the configured
+	// TimeZone), NUMERIC and BIGNUMERIC values are returned as float64 values
+	// when that's lossless (and as strings otherwise), and INTERVAL values
+	// without a year/month part are returned as int64 nanoseconds (which can
+	// be scanned into a time.Duration, where each day counts as 24 hours).
+	TypeMappingNative TypeMapping = "native"
+)
+
+func parseTypeMapping(mapping string) (TypeMapping, error) {
+	switch m := TypeMapping(strings.ToLower(mapping)); m {
+	case "", TypeMappingStrict, TypeMappingLenient, TypeMappingNative:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid type mapping: %s", mapping)
+	}
+}
+
+// Converter is a user-supplied conversion for values of a particular BigQuery
+// type (see [Config.Converters]), which takes precedence over the configured
+// [TypeMapping].
+type Converter struct {
+	// Convert converts a (non-repeated) BigQuery value, as returned by the
+	// [bigquery.RowIterator], to a driver.Value. The value may be nil.
+	Convert func(field *bigquery.FieldSchema, value bigquery.Value) (driver.Value, error)
+
+	// ScanType is the type reported by [sql.ColumnType.ScanType] for columns
+	// of this type. If nil, the default scan type is reported.
+	ScanType reflect.Type
+}
+
+// Returns the scan type for the field, if it's determined by the conversion
+// settings rather than by the field type alone.
+func (conv conversion) scanType(field *bigquery.FieldSchema) (reflect.Type, bool) {
+	if field.Repeated {
+		return nil, false
+	}
+
+	if converter, ok := conv.converters[field.Type]; ok && converter.ScanType != nil {
+		return converter.ScanType, true
+	}
+
+	switch field.Type {
+	case bigquery.GeographyFieldType:
+		if conv.geographyFormat == GeographyWKB || conv.geographyFormat == GeographyGeoJSON {
+			return bytesPtrType, true
+		}
+	case bigquery.JSONFieldType:
+		if conv.jsonRawMessage {
+			return rawMessagePtrType, true
+		}
+	}
+
+	if conv.typeMapping == TypeMappingNative {
+		switch field.Type {
+		case bigquery.DateFieldType, bigquery.DateTimeFieldType:
+			return timePtrType, true
+		case bigquery.NumericFieldType, bigquery.BigNumericFieldType, bigquery.IntervalFieldType:
+			// Either a float64/int64 or a string, depending on the value.
+			return anyType, true
+		}
+	}
+	return nil, false
+}
+
+// Converts the value according to the user-supplied converters and the native
+// type mapping. Reports false if the value should be converted as usual.
+func (conv conversion) convertCustom(field *bigquery.FieldSchema, value bigquery.Value) (any, bool, error) {
+	if converter, ok := conv.converters[field.Type]; ok && converter.Convert != nil {
+		val, err := converter.Convert(field, value)
+		return val, true, err
+	}
+
+	if conv.typeMapping != TypeMappingNative || value == nil {
+		return nil, false, nil
+	}
+
+	switch val := value.(type) {
+	case civil.Date:
+		return val.In(conv.timeZone), true, nil
+	case civil.DateTime:
+		return val.In(conv.timeZone), true, nil
+	case *big.Rat:
+		if f, ok := losslessFloat(val); ok {
+			return f, true, nil
+		}
+	case *bigquery.IntervalValue:
+		if d, ok := intervalDuration(val); ok {
+			return int64(d), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// Converts a value of an unexpected or unsupported type to a string, for the
+// lenient type mapping.
+func (conv conversion) convertLenient(value bigquery.Value, err error) (any, error) {
+	if conv.typeMapping != TypeMappingLenient {
+		return nil, err
+	}
+
+	var typeErr *unexpectedTypeError
+	var fieldTypeErr *invalidFieldTypeError
+	if !errors.As(err, &typeErr) && !errors.As(err, &fieldTypeErr) {
+		return nil, err
+	}
+
+	switch val := value.(type) {
+	case nil:
+		return nil, nil
+	case fmt.Stringer:
+		return val.String(), nil
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
+
+// Returns the rational number as a float64, if the shortest decimal
+// representation of the float64 is exactly equal to the rational number.
+func losslessFloat(r *big.Rat) (float64, bool) {
+	f, _ := r.Float64()
+	if math.IsInf(f, 0) {
+		return 0, false
+	}
+	parsed, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok || parsed.Cmp(r) != 0 {
+		return 0, false
+	}
+	return f, true
+}
+
+// Returns the interval as a duration, if it has no year/month part (which
+// has no fixed duration) and fits in a time.Duration.
+func intervalDuration(iv *bigquery.IntervalValue) (time.Duration, bool) {
+	if iv.Years != 0 || iv.Months != 0 {
+		return 0, false
+	}
+
+	nanos := new(big.Int).SetInt64(int64(iv.Days)*24 + int64(iv.Hours))
+	nanos.Mul(nanos, big.NewInt(60))
+	nanos.Add(nanos, big.NewInt(int64(iv.Minutes)))
+	nanos.Mul(nanos, big.NewInt(60))
+	nanos.Add(nanos, big.NewInt(int64(iv.Seconds)))
+	nanos.Mul(nanos, big.NewInt(int64(time.Second)))
+	nanos.Add(nanos, big.NewInt(int64(iv.SubSecondNanos)))
+	if !nanos.IsInt64() {
+		return 0, false
+	}
+	return time.Duration(nanos.Int64()), true
+}